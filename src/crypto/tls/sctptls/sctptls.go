@@ -0,0 +1,513 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctptls runs TLS over SCTP associations as described in
+// RFC 3436.
+//
+// Following RFC 3436, each bidirectional SCTP stream of an association
+// carries its own TLS connection, and every TLS record is sent as one
+// SCTP user message. The handshake on stream 0 is performed first; TLS
+// connections on other streams share its session and, where the
+// configuration allows it, resume it instead of performing a full
+// handshake. The stream of each connection is preserved: data written
+// to the [*tls.Conn] of a stream is sent on that stream only.
+//
+// An association is typically obtained from [net.SCTPListener.AcceptSCTP]
+// on the server. On the client, the connection returned by
+// [net.DialSCTP] can be used as is, since it carries a single
+// association; [net.SCTPConn.PeelOff] with association 0 sets that
+// association up and turns it into a one-to-one connection:
+//
+//	dc, err := net.DialSCTP("sctp", nil, raddr)
+//	...
+//	assoc, err := dc.PeelOff(0)
+//	dc.Close()
+//	...
+//	conn := sctptls.Client(assoc, config)
+//
+// The peer controls how much is received, so a [Conn] bounds what it
+// buffers: a record larger than TLS allows fails the association, at
+// most 256 streams are open at a time, with data on further streams
+// discarded, and the association is no longer read while a stream
+// has 64 KiB of records waiting to be read.
+package sctptls
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// recordHeaderLen is the length of a TLS record header.
+const recordHeaderLen = 5
+
+// maxRecordLen is the length of the largest TLS record: 2^14 bytes of
+// plaintext expanded by at most 2048 bytes (RFC 5246, section 6.2.3).
+const maxRecordLen = recordHeaderLen + 1<<14 + 2048
+
+const (
+	maxStreams = 256      // streams open at a time
+	maxQueued  = 64 << 10 // bytes of records queued on a stream
+)
+
+var (
+	errClosed         = errors.New("sctptls: stream closed")
+	errRecordTooLarge = errors.New("sctptls: received record too large")
+)
+
+// Conn is a TLS-protected SCTP association.
+type Conn struct {
+	assoc    *net.SCTPConn
+	config   *tls.Config
+	isClient bool
+
+	handshakeOnce sync.Once
+	handshakeErr  error
+
+	mu      sync.Mutex
+	streams map[uint16]*stream
+	tls     map[uint16]*tls.Conn
+	closed  map[uint16]bool // streams closed or refused; their data is discarded
+	readErr error           // sticky error of the read loop
+	opened  []uint16        // streams opened by the peer, for AcceptStream
+	started bool            // read loop started
+
+	acceptChanged chan struct{} // closed and replaced when opened grows or reading fails
+	done          chan struct{} // closed by Close
+}
+
+// Client returns a new TLS client side association using assoc, an SCTP
+// connection carrying a single association. The config cannot be nil.
+//
+// If config.ClientSessionCache is nil, a cache private to the
+// association is used so that streams other than 0 resume the session
+// of stream 0.
+func Client(assoc *net.SCTPConn, config *tls.Config) *Conn {
+	config = config.Clone()
+	if config.ClientSessionCache == nil {
+		config.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	return newConn(assoc, config, true)
+}
+
+// Server returns a new TLS server side association using assoc. The
+// configuration config must be non-nil and must include at least one
+// certificate or else set GetCertificate.
+func Server(assoc *net.SCTPConn, config *tls.Config) *Conn {
+	return newConn(assoc, config, false)
+}
+
+func newConn(assoc *net.SCTPConn, config *tls.Config, isClient bool) *Conn {
+	return &Conn{
+		assoc:    assoc,
+		config:   config,
+		isClient: isClient,
+		streams:  make(map[uint16]*stream),
+		tls:      make(map[uint16]*tls.Conn),
+		closed:   make(map[uint16]bool),
+		done:     make(chan struct{}),
+
+		acceptChanged: make(chan struct{}),
+	}
+}
+
+// Handshake runs the TLS handshake on stream 0 if it has not yet been
+// run. Most uses of this package need not call Handshake explicitly:
+// [Conn.Stream] and [Conn.AcceptStream] call it.
+func (c *Conn) Handshake(ctx context.Context) error {
+	c.handshakeOnce.Do(func() {
+		if err := c.start(); err != nil {
+			c.handshakeErr = err
+			return
+		}
+		c.handshakeErr = c.tlsConn(0).HandshakeContext(ctx)
+	})
+	return c.handshakeErr
+}
+
+// Stream returns the TLS connection carried by stream id, performing
+// the handshake on stream 0 first. Connections on other streams
+// perform their own handshake on first use. A stream cannot be used
+// again once its connection has been closed.
+func (c *Conn) Stream(ctx context.Context, id uint16) (*tls.Conn, error) {
+	if err := c.Handshake(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	closed := c.closed[id]
+	c.mu.Unlock()
+	if closed {
+		return nil, errClosed
+	}
+	return c.tlsConn(id), nil
+}
+
+// AcceptStream waits for the peer to start using a stream other than
+// 0 and returns its TLS connection and stream number. It performs the
+// handshake on stream 0 first.
+func (c *Conn) AcceptStream(ctx context.Context) (*tls.Conn, uint16, error) {
+	if err := c.Handshake(ctx); err != nil {
+		return nil, 0, err
+	}
+	for {
+		c.mu.Lock()
+		for len(c.opened) > 0 {
+			id := c.opened[0]
+			c.opened = c.opened[1:]
+			if c.closed[id] {
+				continue
+			}
+			c.mu.Unlock()
+			return c.tlsConn(id), id, nil
+		}
+		err, changed := c.readErr, c.acceptChanged
+		c.mu.Unlock()
+		if err != nil {
+			return nil, 0, err
+		}
+		select {
+		case <-changed:
+		case <-c.done:
+			return nil, 0, net.ErrClosed
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		}
+	}
+}
+
+// Close closes the association. TLS connections of its streams are
+// not sent close_notify alerts; close them first for an orderly
+// shutdown.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	select {
+	case <-c.done:
+		c.mu.Unlock()
+		return net.ErrClosed
+	default:
+		close(c.done)
+	}
+	c.mu.Unlock()
+	return c.assoc.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *Conn) LocalAddr() net.Addr { return c.assoc.LocalAddr() }
+
+// RemoteAddr returns the primary address of the peer.
+func (c *Conn) RemoteAddr() net.Addr { return c.assoc.RemoteAddr() }
+
+// start enables per-message receive information on the association
+// and starts the loop dispatching received messages to streams.
+func (c *Conn) start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started {
+		return nil
+	}
+	if err := c.assoc.SetRecvRcvInfo(true); err != nil {
+		return err
+	}
+	c.started = true
+	go c.readLoop()
+	return nil
+}
+
+// tlsConn returns the TLS connection of stream id, creating it if
+// needed.
+func (c *Conn) tlsConn(id uint16) *tls.Conn {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tc, ok := c.tls[id]; ok {
+		return tc
+	}
+	s := c.streamLocked(id)
+	var tc *tls.Conn
+	if c.isClient {
+		tc = tls.Client(s, c.config)
+	} else {
+		tc = tls.Server(s, c.config)
+	}
+	c.tls[id] = tc
+	return tc
+}
+
+// streamLocked returns stream id, creating it if needed.
+// The caller must hold c.mu.
+func (c *Conn) streamLocked(id uint16) *stream {
+	s, ok := c.streams[id]
+	if !ok {
+		s = &stream{c: c, id: id, changed: make(chan struct{})}
+		c.streams[id] = s
+	}
+	return s
+}
+
+// peerStream returns stream id for data received from the peer,
+// opening it and queueing it for AcceptStream if needed. It returns
+// nil if the stream was closed, or if it cannot be opened because
+// maxStreams streams are open; the stream is then refused for good.
+func (c *Conn) peerStream(id uint16) *stream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.streams[id]; ok {
+		return s
+	}
+	if c.closed[id] {
+		return nil
+	}
+	if len(c.streams) >= maxStreams {
+		c.closed[id] = true
+		return nil
+	}
+	s := c.streamLocked(id)
+	if id != 0 {
+		c.opened = append(c.opened, id)
+		c.acceptNotifyLocked()
+	}
+	return s
+}
+
+func (c *Conn) readLoop() {
+	buf := make([]byte, 64<<10)
+	partial := make(map[uint16][]byte)
+	for {
+		n, _, flags, _, info, err := c.assoc.ReadFromSCTP(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				err = io.EOF
+			}
+			c.fail(err)
+			return
+		}
+		if flags&net.SCTPMsgNotification != 0 || info == nil {
+			continue
+		}
+		s := c.peerStream(info.Stream)
+		if s == nil {
+			delete(partial, info.Stream)
+			continue
+		}
+		data := append(partial[info.Stream], buf[:n]...)
+		if len(data) > maxRecordLen {
+			c.fail(errRecordTooLarge)
+			return
+		}
+		if flags&net.SCTPMsgEOR == 0 {
+			partial[info.Stream] = data
+			continue
+		}
+		delete(partial, info.Stream)
+		if !s.deliver(data) {
+			c.fail(io.EOF)
+			return
+		}
+	}
+}
+
+// forget removes stream id, closed by the application.
+func (c *Conn) forget(id uint16) {
+	c.mu.Lock()
+	delete(c.streams, id)
+	delete(c.tls, id)
+	c.closed[id] = true
+	c.mu.Unlock()
+}
+
+// acceptNotifyLocked wakes the goroutines waiting in AcceptStream.
+// The caller must hold c.mu.
+func (c *Conn) acceptNotifyLocked() {
+	close(c.acceptChanged)
+	c.acceptChanged = make(chan struct{})
+}
+
+// fail records err as the read error of every stream.
+func (c *Conn) fail(err error) {
+	c.mu.Lock()
+	c.readErr = err
+	c.acceptNotifyLocked()
+	streams := make([]*stream, 0, len(c.streams))
+	for _, s := range c.streams {
+		streams = append(streams, s)
+	}
+	c.mu.Unlock()
+	for _, s := range streams {
+		s.mu.Lock()
+		s.notifyLocked()
+		s.mu.Unlock()
+	}
+}
+
+// A stream is a [net.Conn] carrying the TLS records of one SCTP stream.
+type stream struct {
+	c  *Conn
+	id uint16
+
+	mu           sync.Mutex
+	changed      chan struct{} // closed and replaced when the state below changes
+	queue        [][]byte
+	queued       int // bytes in queue
+	closed       bool
+	readDeadline time.Time
+}
+
+// deliver queues a record received on the stream, waiting while
+// maxQueued bytes are queued, and discards it if the stream is closed.
+// It reports false if the association was closed meanwhile.
+func (s *stream) deliver(b []byte) bool {
+	s.mu.Lock()
+	for !s.closed && s.queued >= maxQueued {
+		changed := s.changed
+		s.mu.Unlock()
+		select {
+		case <-changed:
+		case <-s.c.done:
+			return false
+		}
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.queue = append(s.queue, b)
+		s.queued += len(b)
+		s.notifyLocked()
+	}
+	return true
+}
+
+// notifyLocked wakes the goroutines waiting in Read or deliver. The
+// caller must hold s.mu.
+func (s *stream) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *stream) Read(b []byte) (int, error) {
+	for {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return 0, errClosed
+		}
+		if len(s.queue) > 0 {
+			n := copy(b, s.queue[0])
+			if n == len(s.queue[0]) {
+				s.queue[0] = nil
+				s.queue = s.queue[1:]
+			} else {
+				s.queue[0] = s.queue[0][n:]
+			}
+			s.queued -= n
+			s.notifyLocked()
+			s.mu.Unlock()
+			return n, nil
+		}
+		changed, deadline := s.changed, s.readDeadline
+		s.mu.Unlock()
+
+		s.c.mu.Lock()
+		err := s.c.readErr
+		s.c.mu.Unlock()
+		if err != nil {
+			return 0, err
+		}
+
+		if deadline.IsZero() {
+			<-changed
+			continue
+		}
+		d := time.Until(deadline)
+		if d <= 0 {
+			return 0, os.ErrDeadlineExceeded
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-changed:
+			t.Stop()
+		case <-t.C:
+			return 0, os.ErrDeadlineExceeded
+		}
+	}
+}
+
+// Write sends each complete TLS record in b as one SCTP message on the
+// stream. Package tls only writes whole records.
+func (s *stream) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return 0, errClosed
+	}
+	info := net.SCTPSndInfo{Stream: s.id}
+	written := 0
+	for _, rec := range splitRecords(b) {
+		n, err := s.c.assoc.WriteToSCTP(rec, nil, &info)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// splitRecords splits b at TLS record boundaries. A trailing
+// incomplete record is returned as is.
+func splitRecords(b []byte) [][]byte {
+	var recs [][]byte
+	for len(b) > 0 {
+		n := len(b)
+		if len(b) >= recordHeaderLen {
+			if l := recordHeaderLen + (int(b[3])<<8 | int(b[4])); l < n {
+				n = l
+			}
+		}
+		recs = append(recs, b[:n])
+		b = b[n:]
+	}
+	return recs
+}
+
+// Close closes the stream; the association stays open. Records the
+// peer sends on the stream afterwards are discarded.
+func (s *stream) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errClosed
+	}
+	s.closed = true
+	s.queue, s.queued = nil, 0
+	s.notifyLocked()
+	s.mu.Unlock()
+	s.c.forget(s.id)
+	return nil
+}
+
+func (s *stream) LocalAddr() net.Addr  { return s.c.LocalAddr() }
+func (s *stream) RemoteAddr() net.Addr { return s.c.RemoteAddr() }
+
+func (s *stream) SetDeadline(t time.Time) error {
+	s.SetReadDeadline(t)
+	return s.SetWriteDeadline(t)
+}
+
+func (s *stream) SetReadDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.readDeadline = t
+	s.notifyLocked()
+	s.mu.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the whole association,
+// since every stream writes to the same socket.
+func (s *stream) SetWriteDeadline(t time.Time) error {
+	return s.c.assoc.SetWriteDeadline(t)
+}
@@ -0,0 +1,243 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctptls
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSplitRecords(t *testing.T) {
+	rec := func(n int) []byte {
+		b := make([]byte, recordHeaderLen+n)
+		b[0], b[1], b[2], b[3], b[4] = 23, 3, 3, byte(n>>8), byte(n)
+		return b
+	}
+	a, b := rec(3), rec(300)
+	in := append(append(append([]byte(nil), a...), b...), 22, 3)
+	got := splitRecords(in)
+	want := [][]byte{a, b, {22, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("splitRecords returned %d records; want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("record %d = %x; want %x", i, got[i], want[i])
+		}
+	}
+}
+
+// TestConcurrentWaits checks that a single event wakes every
+// goroutine blocked without a deadline, not just one of them.
+func TestConcurrentWaits(t *testing.T) {
+	const waiters = 4
+	wait := func(name string, done <-chan error, want error) {
+		t.Helper()
+		for range waiters {
+			select {
+			case err := <-done:
+				if err != want {
+					t.Fatalf("%s error = %v; want %v", name, err, want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s missed its wakeup", name)
+			}
+		}
+	}
+
+	c := newConn(nil, nil, false)
+	c.mu.Lock()
+	s := c.streamLocked(1)
+	c.mu.Unlock()
+	done := make(chan error, waiters)
+	for range waiters {
+		go func() {
+			_, err := s.Read(make([]byte, 8))
+			done <- err
+		}()
+	}
+	// Let the readers block first.
+	time.Sleep(20 * time.Millisecond)
+	s.Close()
+	wait("Read", done, errClosed)
+
+	c.handshakeOnce.Do(func() {})
+	for range waiters {
+		go func() {
+			_, _, err := c.AcceptStream(context.Background())
+			done <- err
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	c.fail(io.EOF)
+	wait("AcceptStream", done, io.EOF)
+}
+
+// TestLimits checks that the streams opened by the peer and the
+// records queued on them are bounded.
+func TestLimits(t *testing.T) {
+	c := newConn(nil, nil, false)
+	for id := range uint16(maxStreams) {
+		if c.peerStream(id) == nil {
+			t.Fatalf("stream %d refused", id)
+		}
+	}
+	if c.peerStream(maxStreams) != nil {
+		t.Fatalf("stream %d opened past the limit", maxStreams)
+	}
+	if len(c.opened) != maxStreams-1 {
+		t.Errorf("%d streams to accept; want %d", len(c.opened), maxStreams-1)
+	}
+
+	// Closing a stream makes room and discards its later data.
+	s := c.peerStream(1)
+	s.Close()
+	if c.peerStream(1) != nil {
+		t.Error("data accepted on a closed stream")
+	}
+	if !s.deliver([]byte("late")) || len(s.queue) != 0 {
+		t.Error("record queued on a closed stream")
+	}
+	if c.peerStream(maxStreams+1) == nil {
+		t.Error("stream refused after another was closed")
+	}
+
+	// A full queue blocks the read loop until the stream is read.
+	s = c.peerStream(2)
+	rec := make([]byte, maxQueued)
+	s.deliver(rec)
+	delivered := make(chan bool)
+	go func() { delivered <- s.deliver([]byte("next")) }()
+	select {
+	case <-delivered:
+		t.Fatal("record queued past the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if n, err := s.Read(rec); n != maxQueued || err != nil {
+		t.Fatalf("Read = %d, %v; want %d, nil", n, err, maxQueued)
+	}
+	if !<-delivered || s.queued != len("next") {
+		t.Errorf("%d bytes queued after reading; want %d", s.queued, len("next"))
+	}
+}
+
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sctptls.test"},
+		DNSNames:     []string{"sctptls.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestStreams(t *testing.T) {
+	lc, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	defer lc.Close()
+	ln, err := net.NewSCTPListener(lc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := testCertificate(t)
+	pool := x509.NewCertPool()
+	pool.AddCert(must(x509.ParseCertificate(cert.Certificate[0])))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	served := make(chan error, 1)
+	go func() {
+		assoc, err := ln.AcceptSCTP()
+		if err != nil {
+			served <- err
+			return
+		}
+		srv := Server(assoc, &tls.Config{Certificates: []tls.Certificate{cert}})
+		defer srv.Close()
+		for range 2 {
+			tc, id, err := srv.AcceptStream(ctx)
+			if err != nil {
+				served <- err
+				return
+			}
+			buf := make([]byte, 64)
+			n, err := tc.Read(buf)
+			if err != nil {
+				served <- err
+				return
+			}
+			if _, err := tc.Write(append([]byte{byte(id)}, buf[:n]...)); err != nil {
+				served <- err
+				return
+			}
+		}
+		served <- nil
+	}()
+
+	dc, err := net.DialSCTP("sctp4", nil, ln.Addr().(*net.SCTPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assoc, err := dc.PeelOff(0)
+	dc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli := Client(assoc, &tls.Config{RootCAs: pool, ServerName: "sctptls.test"})
+	defer cli.Close()
+
+	for _, id := range []uint16{3, 5} {
+		tc, err := cli.Stream(ctx, id)
+		if err != nil {
+			t.Fatalf("Stream(%d): %v", id, err)
+		}
+		if _, err := tc.Write([]byte("hello")); err != nil {
+			t.Fatalf("stream %d: Write: %v", id, err)
+		}
+		tc.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 64)
+		n, err := io.ReadAtLeast(tc, buf, 6)
+		if err != nil {
+			t.Fatalf("stream %d: Read: %v", id, err)
+		}
+		if want := append([]byte{byte(id)}, "hello"...); !bytes.Equal(buf[:n], want) {
+			t.Errorf("stream %d: got %q; want %q", id, buf[:n], want)
+		}
+	}
+	if err := <-served; err != nil {
+		t.Fatalf("server: %v", err)
+	}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
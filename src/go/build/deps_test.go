@@ -479,6 +479,9 @@ var depsRules = `
 	NET, log
 	< net/mail;
 
+	FMT, net/netip
+	< net/sctpdiag;
+
 	# FIPS is the FIPS 140 module.
 	# It must not depend on external crypto packages.
 	# Package hash is ok as it's only the interface.
@@ -628,6 +631,9 @@ var depsRules = `
 	crypto/tls
 	< net/smtp;
 
+	crypto/tls
+	< crypto/tls/sctptls;
+
 	crypto/rand
 	< hash/maphash; # for purego implementation
 
@@ -666,10 +672,14 @@ var depsRules = `
 	encoding/json, net/http
 	< expvar;
 
+	expvar < net/sctpvar;
+
 	net/http, net/http/internal/ascii
 	< net/http/cookiejar, net/http/httputil;
 
-	net/http, flag
+	net/http < net/http/sctphttp;
+
+	net/http, net/http/sctphttp, flag
 	< net/http/httptest;
 
 	net/http, regexp
@@ -692,6 +702,8 @@ var depsRules = `
 	< net/rpc
 	< net/rpc/jsonrpc;
 
+	net/rpc < net/rpc/sctprpc;
+
 	# System Information
 	bufio, bytes, internal/cpu, io, os, strings, sync
 	< internal/sysinfo;
@@ -707,6 +719,9 @@ var depsRules = `
 	FMT, DEBUG, flag, runtime/trace, internal/sysinfo, math/rand
 	< testing;
 
+	NET, encoding/binary, testing
+	< net/sctptest;
+
 	testing, math
 	< simd/archsimd/internal/test_helpers;
 
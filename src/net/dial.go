@@ -228,6 +228,14 @@ type Dialer struct {
 	// used, any call to Dial with "tcp(4|6)" as network will use MPTCP if
 	// supported by the operating system.
 	mptcpStatus mptcpStatusDial
+
+	// If sctpMultihome is set, Dial with "sctp(4|6)" as network uses
+	// every resolved address of the host as one multihomed peer set.
+	sctpMultihome bool
+
+	// If sctpFeatures is not nil, SCTP sockets created by Dial offer
+	// these protocol extensions.
+	sctpFeatures *SCTPFeatures
 }
 
 func (d *Dialer) dualStack() bool { return d.FallbackDelay >= 0 }
@@ -429,14 +437,54 @@ func (d *Dialer) SetMultipathTCP(use bool) {
 	d.mptcpStatus.set(use)
 }
 
+// MultihomeSCTP reports whether SCTP dials use every resolved address
+// of the host as peer addresses of a single association.
+func (d *Dialer) MultihomeSCTP() bool {
+	return d.sctpMultihome
+}
+
+// SetMultihomeSCTP directs the [Dial] methods, for the "sctp", "sctp4"
+// and "sctp6" networks, to pass every address the host resolves to
+// to the operating system as the peer addresses of one multihomed
+// association, instead of trying each address in turn.
+//
+// With the "sctp" network the set mixes IPv4 and IPv6 addresses, as
+// with [ResolveSCTPMultiAddr], and the association uses an AF_INET6
+// socket. If the system cannot create dual-stack sockets, only the
+// addresses of the family that [ResolveSCTPAddr] would pick are used.
+// If [Dialer.LocalAddr] is an IPv4 address, only the IPv4 addresses
+// are used.
+func (d *Dialer) SetMultihomeSCTP(use bool) {
+	d.sctpMultihome = use
+}
+
+// FeaturesSCTP returns the SCTP protocol extensions set by
+// [Dialer.SetFeaturesSCTP], and whether they were set.
+func (d *Dialer) FeaturesSCTP() (SCTPFeatures, bool) {
+	if d.sctpFeatures == nil {
+		return SCTPFeatures{}, false
+	}
+	return *d.sctpFeatures, true
+}
+
+// SetFeaturesSCTP directs the [Dial] methods, for the SCTP networks, to
+// configure the protocol extensions the socket offers before it sets
+// up any association, as [SCTPConn.SetFeatures] does. Unlike a call to
+// SetFeatures after the dial, this also covers the association that a
+// dial using [Dialer.SetMultihomeSCTP] sets up before returning.
+func (d *Dialer) SetFeaturesSCTP(f SCTPFeatures) {
+	d.sctpFeatures = &f
+}
+
 // Dial connects to the address on the named network.
 //
 // Known networks are "tcp", "tcp4" (IPv4-only), "tcp6" (IPv6-only),
 // "udp", "udp4" (IPv4-only), "udp6" (IPv6-only), "ip", "ip4"
 // (IPv4-only), "ip6" (IPv6-only), "unix", "unixgram" and
-// "unixpacket".
+// "unixpacket". On Linux, "sctp", "sctp4" (IPv4-only) and "sctp6"
+// (IPv6-only) are also known.
 //
-// For TCP and UDP networks, the address has the form "host:port".
+// For TCP, UDP and SCTP networks, the address has the form "host:port".
 // The host must be a literal IP address, or a host name that can be
 // resolved to IP addresses.
 // The port must be a literal port number or a service name.
@@ -447,7 +495,10 @@ func (d *Dialer) SetMultipathTCP(use bool) {
 // The functions [JoinHostPort] and [SplitHostPort] manipulate a pair of
 // host and port in this form.
 // When using TCP, and the host resolves to multiple IP addresses,
-// Dial will try each IP address in order until one succeeds.
+// Dial will try each IP address in order until one succeeds. So does
+// SCTP, unless [Dialer.SetMultihomeSCTP] is used, in which case all
+// the addresses are given to the operating system as the peer
+// addresses of a single association.
 //
 // Examples:
 //
@@ -551,6 +602,13 @@ func (d *Dialer) DialContext(ctx context.Context, network, address string) (Conn
 		return nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: err}
 	}
 
+	if d.sctpMultihome {
+		switch network {
+		case "sctp", "sctp4", "sctp6":
+			return d.dialSCTPPeerSet(ctx, network, address, addrs)
+		}
+	}
+
 	sd := &sysDialer{
 		Dialer:  *d,
 		network: network,
@@ -857,6 +915,10 @@ type ListenConfig struct {
 	// used, any call to Listen with "tcp(4|6)" as network will use MPTCP if
 	// supported by the operating system.
 	mptcpStatus mptcpStatusListen
+
+	// If sctpFeatures is not nil, SCTP sockets created by Listen offer
+	// these protocol extensions.
+	sctpFeatures *SCTPFeatures
 }
 
 // MultipathTCP reports whether MPTCP will be used.
@@ -877,6 +939,23 @@ func (lc *ListenConfig) SetMultipathTCP(use bool) {
 	lc.mptcpStatus.set(use)
 }
 
+// FeaturesSCTP returns the SCTP protocol extensions set by
+// [ListenConfig.SetFeaturesSCTP], and whether they were set.
+func (lc *ListenConfig) FeaturesSCTP() (SCTPFeatures, bool) {
+	if lc.sctpFeatures == nil {
+		return SCTPFeatures{}, false
+	}
+	return *lc.sctpFeatures, true
+}
+
+// SetFeaturesSCTP directs the [ListenConfig.ListenPacket] method, for
+// the SCTP networks, to configure the protocol extensions the socket
+// offers before it is bound, so that they apply to every association it
+// accepts. See [SCTPConn.SetFeatures].
+func (lc *ListenConfig) SetFeaturesSCTP(f SCTPFeatures) {
+	lc.sctpFeatures = &f
+}
+
 // Listen announces on the local network address.
 //
 // See func Listen for a description of the network and address
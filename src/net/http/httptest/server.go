@@ -16,6 +16,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/internal/testcert"
+	"net/http/sctphttp"
 	"os"
 	"strings"
 	"sync"
@@ -56,6 +57,9 @@ type Server struct {
 	// client is configured for use with the server.
 	// Its transport is automatically closed when Close is called.
 	client *http.Client
+
+	// sctp opens the streams of the client of an SCTP server.
+	sctp *sctphttp.Dialer
 }
 
 func newLocalListener() net.Listener {
@@ -109,6 +113,26 @@ func NewServer(handler http.Handler) *Server {
 	return ts
 }
 
+// NewSCTPServer starts and returns a new [Server] that serves HTTP
+// over SCTP on the loopback interface instead of TCP. Each HTTP
+// connection is a stream of an SCTP association, as set up by the
+// net/http/sctphttp package, which the server's client also uses.
+// The caller should call Close when finished, to shut it down.
+func NewSCTPServer(handler http.Handler) *Server {
+	l, err := sctphttp.Listen("sctp4", "127.0.0.1:0")
+	if err != nil {
+		if l, err = sctphttp.Listen("sctp6", "[::1]:0"); err != nil {
+			panic(fmt.Sprintf("httptest: failed to listen on an SCTP port: %v", err))
+		}
+	}
+	ts := &Server{
+		Listener: l,
+		Config:   &http.Server{Handler: handler},
+	}
+	ts.Start()
+	return ts
+}
+
 // NewUnstartedServer returns a new [Server] but doesn't start it.
 //
 // After changing its configuration, the caller should call Start or
@@ -127,6 +151,9 @@ func (s *Server) Start() {
 	if s.URL != "" {
 		panic("Server already started")
 	}
+	if _, ok := s.Listener.(*sctphttp.Listener); ok {
+		s.sctp = new(sctphttp.Dialer)
+	}
 
 	if s.client == nil {
 		tr := &http.Transport{}
@@ -141,6 +168,9 @@ func (s *Server) Start() {
 			if addr == "example.com:80" || strings.HasSuffix(addr, ".example.com:80") {
 				addr = s.Listener.Addr().String()
 			}
+			if s.sctp != nil {
+				return s.sctp.DialContext(ctx, "sctp", addr)
+			}
 			return dialer.DialContext(ctx, network, addr)
 		}
 		s.client = &http.Client{Transport: tr}
@@ -160,6 +190,9 @@ func (s *Server) StartTLS() {
 	if s.URL != "" {
 		panic("Server already started")
 	}
+	if _, ok := s.Listener.(*sctphttp.Listener); ok {
+		s.sctp = new(sctphttp.Dialer)
+	}
 	if s.client == nil {
 		s.client = &http.Client{}
 	}
@@ -204,6 +237,9 @@ func (s *Server) StartTLS() {
 		if addr == "example.com:443" || strings.HasSuffix(addr, ".example.com:443") {
 			addr = s.Listener.Addr().String()
 		}
+		if s.sctp != nil {
+			return s.sctp.DialContext(ctx, "sctp", addr)
+		}
 		return dialer.DialContext(ctx, network, addr)
 	}
 	s.client.Transport = tr
@@ -275,6 +311,9 @@ func (s *Server) Close() {
 			t.CloseIdleConnections()
 		}
 	}
+	if s.sctp != nil {
+		s.sctp.CloseIdleAssociations()
+	}
 
 	s.wg.Wait()
 }
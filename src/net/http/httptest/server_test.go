@@ -330,3 +330,34 @@ func TestClientExampleCom(t *testing.T) {
 		})
 	}
 }
+
+func TestSCTPServer(t *testing.T) {
+	ln, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	ln.Close()
+
+	ts := NewSCTPServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello over sctp"))
+	}))
+	defer ts.Close()
+	if _, ok := ts.Listener.Addr().(*net.SCTPAddr); !ok {
+		t.Fatalf("listener address is %T; want *net.SCTPAddr", ts.Listener.Addr())
+	}
+
+	for i := 0; i < 3; i++ {
+		res, err := ts.Client().Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "hello over sctp" {
+			t.Fatalf("got %q, want %q", got, "hello over sctp")
+		}
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctphttp carries HTTP/1.1 connections over the streams of
+// SCTP associations.
+//
+// Each HTTP connection is mapped to its own SCTP stream, so requests
+// made concurrently by an [net/http.Transport] share one association to
+// the server without blocking each other: a lost packet or a slow
+// response on one stream does not delay the others. A [Dialer] opens
+// the client side of the streams and a [Listener] accepts them for an
+// [net/http.Server]:
+//
+//	ln, err := sctphttp.Listen("sctp", ":8080")
+//	...
+//	go http.Serve(ln, handler)
+//
+//	d := new(sctphttp.Dialer)
+//	tr := &http.Transport{
+//		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+//			return d.DialContext(ctx, "sctp", addr)
+//		},
+//	}
+//	client := &http.Client{Transport: tr}
+//
+// A Transport always asks for "tcp" connections, so the function set as
+// its DialContext names the SCTP network itself.
+//
+// The end of an HTTP connection is signalled by resetting its stream,
+// which requires RFC 6525 stream reconfiguration; see
+// [net.SCTPFeatures]. Without it, a connection closed by one side is
+// only seen as closed by the other once the association ends.
+package sctphttp
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// DefaultStreams is the number of streams a [Dialer] opens on an
+// association before it sets up the next one. It matches the default
+// number of outbound streams requested by Linux.
+const DefaultStreams = 10
+
+// events are the notifications the streams of both sides rely on to
+// see the end of a connection.
+var events = net.SCTPEventMask{Association: true, StreamReset: true}
+
+// A Dialer opens SCTP streams carrying HTTP connections for an
+// [net/http.Transport]; see the package documentation.
+//
+// Each connection is a new stream of an association to the address.
+// Stream identifiers are not reused, so that the reset ending a closed
+// connection cannot end a new one: once MaxStreams streams have been
+// opened on an association, the next connection sets up a new
+// association, and an association is shut down when its last stream is
+// closed.
+//
+// The zero value is ready to use. A Dialer must not be copied after
+// first use.
+type Dialer struct {
+	// Dialer sets up the associations. Its zero value is usable.
+	Dialer net.Dialer
+
+	// MaxStreams is the number of streams opened per association. If
+	// zero, DefaultStreams is used. It must not exceed the number of
+	// streams the peer accepts.
+	MaxStreams int
+
+	mu     sync.Mutex
+	assocs map[string]*assoc // association with unused streams, by address
+}
+
+// An assoc is an association set up by a Dialer.
+type assoc struct {
+	c    *net.SCTPConn
+	next int  // identifier of the next stream to open
+	open int  // streams opened and not yet closed
+	dead bool // the association ended or all its streams were used
+}
+
+// DialContext opens a stream to the SCTP address addr. The network
+// must be "sctp", "sctp4" or "sctp6".
+func (d *Dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if !sctpNetwork(network) {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: net.UnknownNetworkError(network)}
+	}
+	key := network + " " + addr
+	if s := d.open(key, nil); s != nil {
+		return s, nil
+	}
+	c, err := d.Dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+	sc := c.(*net.SCTPConn)
+	if err := sc.SubscribeEvents(events); err != nil {
+		sc.Close()
+		return nil, err
+	}
+	a := &assoc{c: sc}
+	go d.watch(key, a)
+	return d.open(key, a), nil
+}
+
+// open returns a new stream of the association kept for key, or of a
+// if there is none. It returns nil if neither has a stream left.
+func (d *Dialer) open(key string, a *assoc) *conn {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if cur := d.assocs[key]; cur != nil && !cur.dead {
+		if a != nil {
+			// Another dial set up an association meanwhile; keep
+			// both and use the new one.
+			d.retire(key, cur)
+		} else {
+			a = cur
+		}
+	}
+	if a == nil {
+		return nil
+	}
+	max := d.MaxStreams
+	if max <= 0 {
+		max = DefaultStreams
+	}
+	id := a.next
+	a.next++
+	a.open++
+	if a.next >= max {
+		d.retire(key, a)
+	} else {
+		if d.assocs == nil {
+			d.assocs = make(map[string]*assoc)
+		}
+		d.assocs[key] = a
+	}
+	return &conn{SCTPStream: a.c.Stream(0, uint16(id), 0), d: d, a: a}
+}
+
+// retire stops a from being used for new streams. d.mu must be held.
+func (d *Dialer) retire(key string, a *assoc) {
+	a.dead = true
+	if d.assocs[key] == a {
+		delete(d.assocs, key)
+	}
+	if a.open == 0 {
+		a.c.Close()
+	}
+}
+
+// release records that a stream of a was closed and shuts a down if
+// it was its last.
+func (d *Dialer) release(a *assoc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	a.open--
+	if a.open == 0 && a.dead {
+		a.c.Close()
+	}
+}
+
+// watch consumes the notifications of a, retiring it when the
+// association ends, and discards the messages received on streams
+// that are no longer open.
+func (d *Dialer) watch(key string, a *assoc) {
+	go func() {
+		for range a.c.Messages() {
+		}
+	}()
+	for n := range a.c.Notifications() {
+		if ev, ok := n.(*net.SCTPAssocChangeEvent); ok && ev.State != net.SCTPCommUp && ev.State != net.SCTPRestart {
+			d.mu.Lock()
+			d.retire(key, a)
+			d.mu.Unlock()
+		}
+	}
+}
+
+// CloseIdleAssociations shuts down the associations that have no
+// open stream.
+func (d *Dialer) CloseIdleAssociations() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for key, a := range d.assocs {
+		if a.open == 0 {
+			d.retire(key, a)
+		}
+	}
+}
+
+// A conn is a stream opened by a Dialer.
+type conn struct {
+	*net.SCTPStream
+	d    *Dialer
+	a    *assoc
+	once sync.Once
+}
+
+func (c *conn) Close() error {
+	err := c.SCTPStream.Close()
+	c.once.Do(func() { c.d.release(c.a) })
+	return err
+}
+
+func sctpNetwork(network string) bool {
+	switch network {
+	case "sctp", "sctp4", "sctp6":
+		return true
+	}
+	return false
+}
+
+// A Listener accepts the streams of the associations of an SCTP
+// socket as connections, for use with [net/http.Server.Serve].
+type Listener struct {
+	c *net.SCTPConn
+}
+
+// Listen announces on the local SCTP address. The network must be
+// "sctp", "sctp4" or "sctp6".
+func Listen(network, address string) (*Listener, error) {
+	if !sctpNetwork(network) {
+		return nil, &net.OpError{Op: "listen", Net: network, Err: net.UnknownNetworkError(network)}
+	}
+	addr, err := net.ResolveSCTPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	c, err := net.ListenSCTP(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := NewListener(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// NewListener returns a listener accepting the streams of c, which
+// must be a socket returned by [net.ListenSCTP]. The listener takes
+// over reading from c and subscribes it to the association and stream
+// reset events.
+func NewListener(c *net.SCTPConn) (*Listener, error) {
+	if err := c.SubscribeEvents(events); err != nil {
+		return nil, err
+	}
+	return &Listener{c: c}, nil
+}
+
+// Accept waits for the first message on a stream with no open
+// connection and returns a connection on that stream. Closing the
+// connection resets the stream, after which the client can no longer
+// use it.
+func (l *Listener) Accept() (net.Conn, error) {
+	s, err := l.c.AcceptStream()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the socket, ending the associations and connections of
+// the listener.
+func (l *Listener) Close() error {
+	return l.c.Close()
+}
+
+// Addr returns the local address of the listener.
+func (l *Listener) Addr() net.Addr {
+	return l.c.LocalAddr()
+}
+
+var _ net.Listener = (*Listener)(nil)
@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctphttp
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestSCTPNetwork(t *testing.T) {
+	for _, tt := range []struct {
+		in string
+		ok bool
+	}{
+		{"sctp", true},
+		{"sctp4", true},
+		{"sctp6", true},
+		{"tcp", false},
+		{"tcp4", false},
+		{"udp", false},
+	} {
+		if ok := sctpNetwork(tt.in); ok != tt.ok {
+			t.Errorf("sctpNetwork(%q) = %v; want %v", tt.in, ok, tt.ok)
+		}
+	}
+	var d Dialer
+	for _, network := range []string{"tcp", "udp"} {
+		if _, err := d.DialContext(context.Background(), network, "127.0.0.1:80"); err == nil {
+			t.Errorf("DialContext with %s succeeded", network)
+		}
+	}
+}
+
+func TestServe(t *testing.T) {
+	ln, err := Listen("sctp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	// The first request is answered only once the second one has been
+	// served, which requires them to run on different streams.
+	first, second := make(chan struct{}), make(chan struct{})
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/first" {
+			close(first)
+			<-second
+		} else {
+			close(second)
+		}
+		io.WriteString(w, r.URL.Path)
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	d := &Dialer{MaxStreams: 4}
+	defer d.CloseIdleAssociations()
+	tr := &http.Transport{
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			return d.DialContext(ctx, "sctp4", addr)
+		},
+	}
+	defer tr.CloseIdleConnections()
+	client := &http.Client{Transport: tr}
+
+	url := "http://" + ln.Addr().String()
+	var wg sync.WaitGroup
+	for _, path := range []string{"/first", "/second"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(url + path)
+			if err != nil {
+				t.Errorf("GET %s: %v", path, err)
+				return
+			}
+			defer resp.Body.Close()
+			b, err := io.ReadAll(resp.Body)
+			if err != nil || string(b) != path {
+				t.Errorf("GET %s: got %q, %v", path, b, err)
+			}
+		}()
+		if path == "/first" {
+			<-first
+		}
+	}
+	wg.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, a := range d.assocs {
+		if a.next != 2 {
+			t.Errorf("opened %d streams on the association, want 2", a.next)
+		}
+	}
+	if len(d.assocs) != 1 {
+		t.Errorf("got %d associations, want 1", len(d.assocs))
+	}
+}
+
+func TestDialerRetiresAssociations(t *testing.T) {
+	ln, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	defer ln.Close()
+
+	d := &Dialer{MaxStreams: 2}
+	addr := ln.LocalAddr().String()
+	var conns []net.Conn
+	for range 3 {
+		c, err := d.DialContext(context.Background(), "sctp4", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, c)
+	}
+	a0, a2 := conns[0].(*conn).a, conns[2].(*conn).a
+	if conns[1].(*conn).a != a0 || a2 == a0 {
+		t.Fatal("streams not spread over associations of MaxStreams streams")
+	}
+	if !a0.dead || a2.dead {
+		t.Errorf("dead = %v, %v; want true, false", a0.dead, a2.dead)
+	}
+	for _, c := range conns {
+		c.Close()
+	}
+	if a0.open != 0 || a2.open != 0 {
+		t.Errorf("open = %d, %d after Close; want 0, 0", a0.open, a2.open)
+	}
+	d.CloseIdleAssociations()
+	if len(d.assocs) != 0 {
+		t.Errorf("%d associations left after CloseIdleAssociations", len(d.assocs))
+	}
+}
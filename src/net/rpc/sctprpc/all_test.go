@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctprpc
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"sync"
+	"testing"
+)
+
+type Args struct {
+	A, B int
+}
+
+type Reply struct {
+	C int
+}
+
+type Arith int
+
+func (t *Arith) Add(args *Args, reply *Reply) error {
+	reply.C = args.A + args.B
+	return nil
+}
+
+func (t *Arith) Div(args *Args, reply *Reply) error {
+	if args.B == 0 {
+		return errors.New("divide by zero")
+	}
+	reply.C = args.A / args.B
+	return nil
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	for _, ppid := range []uint32{GobPPID, JSONPPID} {
+		b, err := encodeMessage(ppid, &rpc.Request{ServiceMethod: "Arith.Add", Seq: 7}, &Args{7, 8})
+		if err != nil {
+			t.Fatalf("ppid %#x: encodeMessage: %v", ppid, err)
+		}
+		dec := newDecoder(ppid, b)
+		var req rpc.Request
+		var args Args
+		if err := dec.Decode(&req); err != nil {
+			t.Fatalf("ppid %#x: decode header: %v", ppid, err)
+		}
+		if err := dec.Decode(&args); err != nil {
+			t.Fatalf("ppid %#x: decode body: %v", ppid, err)
+		}
+		if req.ServiceMethod != "Arith.Add" || req.Seq != 7 || args != (Args{7, 8}) {
+			t.Errorf("ppid %#x: got %+v %+v", ppid, req, args)
+		}
+	}
+	if _, err := encodeMessage(1, &rpc.Request{}, nil); err != errUnknownPPID {
+		t.Errorf("encodeMessage with unknown PPID: got %v, want %v", err, errUnknownPPID)
+	}
+}
+
+func TestServer(t *testing.T) {
+	ln, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	srv := rpc.NewServer()
+	if err := srv.Register(new(Arith)); err != nil {
+		t.Fatal(err)
+	}
+	go srv.ServeCodec(NewServerCodec(ln))
+	defer ln.Close()
+
+	raddr := ln.LocalAddr().(*net.SCTPAddr)
+	for _, ppid := range []uint32{GobPPID, JSONPPID} {
+		conn, err := net.DialSCTP("sctp4", nil, raddr)
+		if err != nil {
+			t.Fatalf("DialSCTP: %v", err)
+		}
+		client := rpc.NewClientWithCodec(NewClientCodec(conn, ppid))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 2*DefaultStreams; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var reply Reply
+				if err := client.Call("Arith.Add", &Args{i, i * i}, &reply); err != nil {
+					t.Errorf("ppid %#x: Add: %v", ppid, err)
+					return
+				}
+				if reply.C != i+i*i {
+					t.Errorf("ppid %#x: Add(%d, %d) = %d", ppid, i, i*i, reply.C)
+				}
+			}()
+		}
+		wg.Wait()
+
+		var reply Reply
+		err = client.Call("Arith.Div", &Args{1, 0}, &reply)
+		if err == nil || err.Error() != "divide by zero" {
+			t.Errorf("ppid %#x: Div by zero: got %v", ppid, err)
+		}
+		client.Close()
+	}
+}
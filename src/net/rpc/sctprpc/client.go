@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctprpc
+
+import (
+	"net"
+	"net/rpc"
+)
+
+type clientCodec struct {
+	conn    *net.SCTPConn
+	ppid    uint32
+	streams int
+	r       *reader
+
+	// decoder of the response being read
+	dec decoder
+}
+
+// NewClientCodec returns a new [rpc.ClientCodec] that sends calls on
+// conn, an association created by [net.DialSCTP], encoded as
+// identified by ppid. Calls are spread over [DefaultStreams] streams.
+func NewClientCodec(conn *net.SCTPConn, ppid uint32) rpc.ClientCodec {
+	return &clientCodec{
+		conn:    conn,
+		ppid:    ppid,
+		streams: DefaultStreams,
+		r:       newReader(conn),
+	}
+}
+
+func (c *clientCodec) WriteRequest(r *rpc.Request, param any) error {
+	b, err := encodeMessage(c.ppid, r, param)
+	if err != nil {
+		return err
+	}
+	info := net.SCTPSndInfo{
+		Stream: uint16(r.Seq % uint64(c.streams)),
+		PPID:   c.ppid,
+	}
+	_, err = c.conn.WriteToSCTP(b, nil, &info)
+	return err
+}
+
+func (c *clientCodec) ReadResponseHeader(r *rpc.Response) error {
+	for {
+		b, _, _, err := c.r.next()
+		if err != nil {
+			return err
+		}
+		dec := newDecoder(c.ppid, b)
+		if dec == nil {
+			return errUnknownPPID
+		}
+		*r = rpc.Response{}
+		if err := dec.Decode(r); err != nil {
+			// Not a response of this codec.
+			continue
+		}
+		c.dec = dec
+		return nil
+	}
+}
+
+func (c *clientCodec) ReadResponseBody(x any) error {
+	dec := c.dec
+	c.dec = nil
+	if x == nil || dec == nil {
+		return nil
+	}
+	return dec.Decode(x)
+}
+
+func (c *clientCodec) Close() error {
+	return c.conn.Close()
+}
+
+// NewClient returns a new [rpc.Client] to handle requests to the
+// set of services at the other end of the association, using gob
+// encoding.
+func NewClient(conn *net.SCTPConn) *rpc.Client {
+	return rpc.NewClientWithCodec(NewClientCodec(conn, GobPPID))
+}
+
+// Dial connects to an SCTP RPC server at the specified network address.
+func Dial(network, address string) (*rpc.Client, error) {
+	raddr, err := net.ResolveSCTPAddr(network, address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialSCTP(network, nil, raddr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctprpc implements a ClientCodec and ServerCodec for the rpc
+// package that carry each request and each response in a single SCTP
+// message.
+//
+// Because SCTP preserves message boundaries, no framing is added to
+// the encoded values. A client spreads concurrent calls over several
+// SCTP streams, so a large or slow reply on one stream does not delay
+// replies on the others. A server codec reads from a one-to-many SCTP
+// socket and serves every client associated with it.
+//
+// The payload protocol identifier (PPID) of each message names its
+// encoding: [GobPPID] for encoding/gob and [JSONPPID] for
+// encoding/json. A server replies with the encoding of the request.
+package sctprpc
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+)
+
+// Payload protocol identifiers of the supported message encodings.
+const (
+	GobPPID  uint32 = 0x474f4252 // "GOBR"
+	JSONPPID uint32 = 0x4a534f4e // "JSON"
+)
+
+// DefaultStreams is the number of outbound streams a client spreads
+// its calls over. It matches the default number of outbound streams
+// requested by Linux.
+const DefaultStreams = 10
+
+// msgEOR is MSG_EOR on Linux, set on the last read of a message.
+const msgEOR = 0x80
+
+type encoder interface {
+	Encode(v any) error
+}
+
+type decoder interface {
+	Decode(v any) error
+}
+
+// encodeMessage encodes header and body into one message using the
+// encoding identified by ppid. Every message carries its own gob type
+// information, since messages on different streams may be delivered
+// in any order.
+func encodeMessage(ppid uint32, header, body any) ([]byte, error) {
+	var buf bytes.Buffer
+	var enc encoder
+	switch ppid {
+	case GobPPID:
+		enc = gob.NewEncoder(&buf)
+	case JSONPPID:
+		enc = json.NewEncoder(&buf)
+	default:
+		return nil, errUnknownPPID
+	}
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(body); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// newDecoder returns a decoder of message b, or nil if ppid names no
+// supported encoding.
+func newDecoder(ppid uint32, b []byte) decoder {
+	switch ppid {
+	case GobPPID:
+		return gob.NewDecoder(bytes.NewReader(b))
+	case JSONPPID:
+		return json.NewDecoder(bytes.NewReader(b))
+	}
+	return nil
+}
+
+var errUnknownPPID = errors.New("sctprpc: unknown payload protocol identifier")
+
+type msgKey struct {
+	assocID int32
+	stream  uint16
+}
+
+// A reader reassembles user messages read from an SCTP socket,
+// skipping event notifications.
+type reader struct {
+	conn    *net.SCTPConn
+	buf     []byte
+	partial map[msgKey][]byte
+}
+
+func newReader(conn *net.SCTPConn) *reader {
+	return &reader{
+		conn:    conn,
+		buf:     make([]byte, 64<<10),
+		partial: make(map[msgKey][]byte),
+	}
+}
+
+// next returns the next complete user message. Messages are keyed by
+// association and stream while they are reassembled, so partial
+// deliveries of different streams may interleave.
+func (r *reader) next() ([]byte, *net.SCTPAddr, *net.SCTPRcvInfo, error) {
+	for {
+		n, _, flags, addr, info, err := r.conn.ReadFromSCTP(r.buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				err = io.EOF
+			}
+			return nil, nil, nil, err
+		}
+		if flags&net.SCTPMsgNotification != 0 {
+			continue
+		}
+		var key msgKey
+		if info != nil {
+			key = msgKey{info.AssocID, info.Stream}
+		}
+		data := append(r.partial[key], r.buf[:n]...)
+		if flags&msgEOR == 0 {
+			r.partial[key] = data
+			continue
+		}
+		delete(r.partial, key)
+		return data, addr, info, nil
+	}
+}
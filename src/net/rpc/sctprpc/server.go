@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctprpc
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"sync"
+)
+
+// A request is the origin of a call being served, used to route the
+// response back to the caller.
+type request struct {
+	addr    *net.SCTPAddr
+	assocID int32
+	stream  uint16
+	ppid    uint32
+	seq     uint64 // sequence number chosen by the client
+}
+
+type serverCodec struct {
+	conn *net.SCTPConn
+	r    *reader
+	err  error // error enabling SCTP_RECVRCVINFO
+
+	// decoder of the request being read
+	dec decoder
+
+	// Clients choose their sequence numbers independently, so two
+	// calls on one socket may carry the same number. Package rpc
+	// expects unique sequence numbers; we assign our own to incoming
+	// requests and save the origin of the call in the pending map.
+	mutex   sync.Mutex // protects seq, pending
+	seq     uint64
+	pending map[uint64]request
+}
+
+// NewServerCodec returns a new [rpc.ServerCodec] that serves calls
+// arriving on conn, typically a one-to-many socket created by
+// [net.ListenSCTP]. It enables SCTP_RECVRCVINFO on conn. Each response
+// is sent on the association and stream of its request, with the same
+// encoding.
+func NewServerCodec(conn *net.SCTPConn) rpc.ServerCodec {
+	return &serverCodec{
+		conn:    conn,
+		r:       newReader(conn),
+		err:     conn.SetRecvRcvInfo(true),
+		pending: make(map[uint64]request),
+	}
+}
+
+var errMissingRcvInfo = errors.New("sctprpc: message received without SCTP receive information")
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	if c.err != nil {
+		return c.err
+	}
+	for {
+		b, addr, info, err := c.r.next()
+		if err != nil {
+			return err
+		}
+		if info == nil {
+			return errMissingRcvInfo
+		}
+		dec := newDecoder(info.PPID, b)
+		if dec == nil {
+			// Neither gob nor JSON; we cannot reply.
+			continue
+		}
+		*r = rpc.Request{}
+		if err := dec.Decode(r); err != nil {
+			// A malformed message from one client must not stop
+			// the server for the others.
+			continue
+		}
+
+		c.mutex.Lock()
+		c.seq++
+		c.pending[c.seq] = request{
+			addr:    addr,
+			assocID: info.AssocID,
+			stream:  info.Stream,
+			ppid:    info.PPID,
+			seq:     r.Seq,
+		}
+		r.Seq = c.seq
+		c.mutex.Unlock()
+		c.dec = dec
+		return nil
+	}
+}
+
+func (c *serverCodec) ReadRequestBody(x any) error {
+	dec := c.dec
+	c.dec = nil
+	if x == nil || dec == nil {
+		return nil
+	}
+	return dec.Decode(x)
+}
+
+func (c *serverCodec) WriteResponse(r *rpc.Response, x any) error {
+	c.mutex.Lock()
+	req, ok := c.pending[r.Seq]
+	if !ok {
+		c.mutex.Unlock()
+		return errors.New("invalid sequence number in response")
+	}
+	delete(c.pending, r.Seq)
+	c.mutex.Unlock()
+
+	r.Seq = req.seq
+	b, err := encodeMessage(req.ppid, r, x)
+	if err != nil {
+		return err
+	}
+	info := net.SCTPSndInfo{
+		Stream:  req.stream,
+		PPID:    req.ppid,
+		AssocID: req.assocID,
+	}
+	_, err = c.conn.WriteToSCTP(b, req.addr, &info)
+	return err
+}
+
+func (c *serverCodec) Close() error {
+	return c.conn.Close()
+}
+
+// ServeConn runs the SCTP RPC server on a single socket, serving every
+// client associated with it. ServeConn blocks until the socket is
+// closed.
+func ServeConn(conn *net.SCTPConn) {
+	rpc.ServeCodec(NewServerCodec(conn))
+}
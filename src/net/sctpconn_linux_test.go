@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package net_test
+
+import (
+	"net"
+	"net/sctptest"
+	"testing"
+)
+
+func TestSCTPConnConformance(t *testing.T) {
+	probe, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("kernel SCTP unavailable: %v", err)
+	}
+	probe.Close()
+
+	sctptest.TestConn(t, func() (c1, c2 sctptest.MessageConn, stop func(), err error) {
+		lc, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		ln, err := net.NewSCTPListener(lc)
+		if err != nil {
+			lc.Close()
+			return nil, nil, nil, err
+		}
+		type result struct {
+			c   *net.SCTPConn
+			err error
+		}
+		accepted := make(chan result, 1)
+		go func() {
+			c, err := ln.AcceptSCTP()
+			accepted <- result{c, err}
+		}()
+		dc, err := net.DialSCTP("sctp4", nil, ln.Addr().(*net.SCTPAddr))
+		if err != nil {
+			ln.Close()
+			return nil, nil, nil, err
+		}
+		cli, err := dc.PeelOff(0)
+		dc.Close()
+		if err != nil {
+			ln.Close()
+			return nil, nil, nil, err
+		}
+		r := <-accepted
+		if r.err != nil {
+			cli.Close()
+			ln.Close()
+			return nil, nil, nil, r.err
+		}
+		stop = func() {
+			cli.Close()
+			r.c.Close()
+			ln.Close()
+		}
+		return cli, r.c, stop, nil
+	})
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctpdiag
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"slices"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Linux inet_diag constants that are not provided by the frozen syscall package.
+const (
+	sockDiagByFamily = 20
+
+	inetDiagInfo   = 2
+	inetDiagLocals = 12
+	inetDiagPeers  = 13
+
+	sizeofSockaddrStorage = 128
+)
+
+type inetDiagSockID struct {
+	Sport  [2]byte
+	Dport  [2]byte
+	Src    [16]byte
+	Dst    [16]byte
+	If     uint32
+	Cookie [2]uint32
+}
+
+type inetDiagReqV2 struct {
+	Family   uint8
+	Protocol uint8
+	Ext      uint8
+	_        uint8
+	States   uint32
+	ID       inetDiagSockID
+}
+
+type inetDiagMsg struct {
+	Family  uint8
+	State   uint8
+	Timer   uint8
+	Retrans uint8
+	ID      inetDiagSockID
+	Expires uint32
+	RQueue  uint32
+	WQueue  uint32
+	UID     uint32
+	Inode   uint32
+}
+
+// sctpInfo mirrors the leading part of struct sctp_info, up to and
+// including the primary path RTO.
+type sctpInfo struct {
+	_        [168]byte
+	PAddress [sizeofSockaddrStorage]byte
+	PState   int32
+	PCwnd    uint32
+	PSrtt    uint32
+	PRTO     uint32
+}
+
+const (
+	sizeofInetDiagReqV2 = int(unsafe.Sizeof(inetDiagReqV2{}))
+	sizeofInetDiagMsg   = int(unsafe.Sizeof(inetDiagMsg{}))
+	sizeofSCTPInfo      = int(unsafe.Sizeof(sctpInfo{}))
+)
+
+// NetlinkSockets returns every SCTP endpoint and association on the
+// host by querying the kernel through NETLINK_SOCK_DIAG. It requires
+// the sctp_diag kernel module.
+//
+// The kernel does not report association identifiers over netlink;
+// NetlinkSockets fills [Socket.AssocID] from /proc/net/sctp/assocs
+// when that file is readable.
+func NetlinkSockets() ([]Socket, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_INET_DIAG)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	defer syscall.Close(fd)
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	var out []Socket
+	for i, family := range []uint8{syscall.AF_INET, syscall.AF_INET6} {
+		socks, err := dumpFamily(fd, uint32(i+1), family)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, socks...)
+	}
+	fillAssocIDs(out)
+	return out, nil
+}
+
+func dumpFamily(fd int, seq uint32, family uint8) ([]Socket, error) {
+	req := inetDiagReqV2{
+		Family:   family,
+		Protocol: syscall.IPPROTO_SCTP,
+		Ext:      1 << (inetDiagInfo - 1),
+		States:   ^uint32(0),
+	}
+	b := make([]byte, syscall.NLMSG_HDRLEN+sizeofInetDiagReqV2)
+	h := (*syscall.NlMsghdr)(unsafe.Pointer(&b[0]))
+	h.Len = uint32(len(b))
+	h.Type = sockDiagByFamily
+	h.Flags = syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP
+	h.Seq = seq
+	copy(b[syscall.NLMSG_HDRLEN:], unsafe.Slice((*byte)(unsafe.Pointer(&req)), sizeofInetDiagReqV2))
+	if err := syscall.Sendto(fd, b, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, os.NewSyscallError("sendto", err)
+	}
+
+	var out []Socket
+	rb := make([]byte, 32*1024)
+	for {
+		n, _, err := syscall.Recvfrom(fd, rb, 0)
+		if err != nil {
+			return nil, os.NewSyscallError("recvfrom", err)
+		}
+		msgs, err := syscall.ParseNetlinkMessage(rb[:n])
+		if err != nil {
+			return nil, os.NewSyscallError("parsenetlinkmessage", err)
+		}
+		for _, m := range msgs {
+			if m.Header.Seq != seq {
+				continue
+			}
+			switch m.Header.Type {
+			case syscall.NLMSG_DONE:
+				return out, nil
+			case syscall.NLMSG_ERROR:
+				if len(m.Data) < 4 {
+					return nil, errors.New("sctpdiag: short netlink error message")
+				}
+				errno := -*(*int32)(unsafe.Pointer(&m.Data[0]))
+				if errno == 0 {
+					continue
+				}
+				return nil, os.NewSyscallError("sock_diag", syscall.Errno(errno))
+			case sockDiagByFamily:
+				s, err := parseDiagMsg(m.Data)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, s)
+			}
+		}
+	}
+}
+
+func parseDiagMsg(b []byte) (Socket, error) {
+	if len(b) < sizeofInetDiagMsg {
+		return Socket{}, errors.New("sctpdiag: short inet_diag_msg")
+	}
+	var m inetDiagMsg
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&m)), sizeofInetDiagMsg), b)
+	s := Socket{
+		LocalPort: uint16(m.ID.Sport[0])<<8 | uint16(m.ID.Sport[1]),
+		PeerPort:  uint16(m.ID.Dport[0])<<8 | uint16(m.ID.Dport[1]),
+		TxQueue:   m.WQueue,
+		RxQueue:   m.RQueue,
+		UID:       m.UID,
+		Inode:     uint64(m.Inode),
+	}
+	var isAssoc bool
+	attrs := b[nlmAlign(sizeofInetDiagMsg):]
+	for len(attrs) >= syscall.SizeofRtAttr {
+		a := (*syscall.RtAttr)(unsafe.Pointer(&attrs[0]))
+		if int(a.Len) < syscall.SizeofRtAttr || int(a.Len) > len(attrs) {
+			return Socket{}, errors.New("sctpdiag: malformed netlink attribute")
+		}
+		data := attrs[syscall.SizeofRtAttr:a.Len]
+		switch a.Type {
+		case inetDiagLocals:
+			s.LocalAddrs = parseSockaddrs(data)
+		case inetDiagPeers:
+			isAssoc = true
+			s.PeerAddrs = parseSockaddrs(data)
+		case inetDiagInfo:
+			if len(data) >= sizeofSCTPInfo {
+				var info sctpInfo
+				copy(unsafe.Slice((*byte)(unsafe.Pointer(&info)), sizeofSCTPInfo), data)
+				if addrs := parseSockaddrs(info.PAddress[:]); len(addrs) > 0 {
+					s.Primary = addrs[0]
+				}
+				s.RTO = time.Duration(info.PRTO) * time.Millisecond
+			}
+		}
+		attrs = attrs[min(nlmAlign(int(a.Len)), len(attrs)):]
+	}
+	if isAssoc {
+		s.Kind = Association
+		s.State = State(m.State)
+	} else {
+		s.Kind = Endpoint
+		s.State = endpointState(int(m.State))
+	}
+	return s, nil
+}
+
+// parseSockaddrs decodes the array of sockaddr_storage entries carried
+// by INET_DIAG_LOCALS and INET_DIAG_PEERS.
+func parseSockaddrs(b []byte) []netip.Addr {
+	var out []netip.Addr
+	for ; len(b) >= sizeofSockaddrStorage; b = b[sizeofSockaddrStorage:] {
+		switch *(*uint16)(unsafe.Pointer(&b[0])) {
+		case syscall.AF_INET:
+			out = append(out, netip.AddrFrom4([4]byte(b[4:8])))
+		case syscall.AF_INET6:
+			out = append(out, netip.AddrFrom16([16]byte(b[8:24])))
+		}
+	}
+	return out
+}
+
+// fillAssocIDs copies association identifiers from /proc/net/sctp/assocs
+// into the matching associations of socks.
+func fillAssocIDs(socks []Socket) {
+	assocs, err := parseFile(procAssocs, ParseAssocs)
+	if err != nil {
+		return
+	}
+	for i := range socks {
+		s := &socks[i]
+		if s.Kind != Association {
+			continue
+		}
+		for _, a := range assocs {
+			if a.Inode == s.Inode && a.LocalPort == s.LocalPort && a.PeerPort == s.PeerPort &&
+				(!s.Primary.IsValid() || slices.Contains(a.PeerAddrs, s.Primary)) {
+				s.AssocID = a.AssocID
+				break
+			}
+		}
+	}
+}
+
+func nlmAlign(n int) int {
+	return (n + syscall.NLMSG_ALIGNTO - 1) &^ (syscall.NLMSG_ALIGNTO - 1)
+}
@@ -0,0 +1,14 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+
+package sctpdiag
+
+// NetlinkSockets returns every SCTP endpoint and association on the
+// host by querying the kernel through NETLINK_SOCK_DIAG. It is only
+// supported on Linux.
+func NetlinkSockets() ([]Socket, error) {
+	return nil, errUnsupported
+}
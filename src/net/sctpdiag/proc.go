@@ -0,0 +1,250 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctpdiag
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Locations of the SCTP proc files on Linux.
+const (
+	procEndpoints   = "/proc/net/sctp/eps"
+	procAssocs      = "/proc/net/sctp/assocs"
+	procRemoteAddrs = "/proc/net/sctp/remaddr"
+	procSNMP        = "/proc/net/sctp/snmp"
+)
+
+// Linux socket states (sk_state) reported for SCTP endpoints.
+const (
+	tcpEstablished = 1
+	tcpListen      = 10
+)
+
+// RemoteAddr describes one peer transport address of an association,
+// as listed in /proc/net/sctp/remaddr.
+type RemoteAddr struct {
+	Addr       netip.Addr
+	AssocID    int32
+	HBActive   bool   // heartbeat timer running
+	RTO        uint64 // retransmission timeout, in kernel jiffies
+	MaxPathRtx int    // path.max.retrans
+	ErrorCount int    // retransmissions since the path was last confirmed
+	State      int    // SCTP_INACTIVE, SCTP_PF, SCTP_ACTIVE, ...
+}
+
+// ProcSockets returns every SCTP endpoint and association on the host
+// by reading /proc/net/sctp/eps and /proc/net/sctp/assocs.
+func ProcSockets() ([]Socket, error) {
+	eps, err := parseFile(procEndpoints, ParseEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	assocs, err := parseFile(procAssocs, ParseAssocs)
+	if err != nil {
+		return nil, err
+	}
+	return append(eps, assocs...), nil
+}
+
+// RemoteAddrs returns the peer transport addresses of every association
+// on the host by reading /proc/net/sctp/remaddr.
+func RemoteAddrs() ([]RemoteAddr, error) {
+	return parseFile(procRemoteAddrs, ParseRemoteAddrs)
+}
+
+// SNMP returns the SCTP MIB counters from /proc/net/sctp/snmp,
+// keyed by counter name, such as "SctpCurrEstab".
+func SNMP() (map[string]uint64, error) {
+	return parseFile(procSNMP, ParseSNMP)
+}
+
+func parseFile[T any](name string, parse func(io.Reader) (T, error)) (T, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	defer f.Close()
+	return parse(f)
+}
+
+// ParseEndpoints parses the contents of /proc/net/sctp/eps.
+func ParseEndpoints(r io.Reader) ([]Socket, error) {
+	// ENDPT SOCK STY SST HBKT LPORT UID INODE LADDRS
+	const nfixed = 8
+	var out []Socket
+	err := scanTable(r, func(f []string) error {
+		if len(f) < nfixed {
+			return errors.New("sctpdiag: short line in " + procEndpoints)
+		}
+		s := Socket{Kind: Endpoint}
+		sst, err1 := strconv.Atoi(f[3])
+		lport, err2 := strconv.ParseUint(f[5], 10, 16)
+		uid, err3 := strconv.ParseUint(f[6], 10, 32)
+		inode, err4 := strconv.ParseUint(f[7], 10, 64)
+		if err := errors.Join(err1, err2, err3, err4); err != nil {
+			return err
+		}
+		s.State = endpointState(sst)
+		s.LocalPort = uint16(lport)
+		s.UID = uint32(uid)
+		s.Inode = inode
+		s.LocalAddrs, _, _ = parseAddrList(f[nfixed:])
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+// ParseAssocs parses the contents of /proc/net/sctp/assocs.
+func ParseAssocs(r io.Reader) ([]Socket, error) {
+	// ASSOC SOCK STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE
+	// LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS ...
+	const nfixed = 13
+	var out []Socket
+	err := scanTable(r, func(f []string) error {
+		if len(f) < nfixed {
+			return errors.New("sctpdiag: short line in " + procAssocs)
+		}
+		s := Socket{Kind: Association}
+		st, err1 := strconv.ParseUint(f[4], 10, 8)
+		id, err2 := strconv.ParseInt(f[6], 10, 32)
+		tx, err3 := strconv.ParseUint(f[7], 10, 32)
+		rx, err4 := strconv.ParseUint(f[8], 10, 32)
+		uid, err5 := strconv.ParseUint(f[9], 10, 32)
+		inode, err6 := strconv.ParseUint(f[10], 10, 64)
+		lport, err7 := strconv.ParseUint(f[11], 10, 16)
+		rport, err8 := strconv.ParseUint(f[12], 10, 16)
+		if err := errors.Join(err1, err2, err3, err4, err5, err6, err7, err8); err != nil {
+			return err
+		}
+		s.State = State(st)
+		s.AssocID = int32(id)
+		s.TxQueue = uint32(tx)
+		s.RxQueue = uint32(rx)
+		s.UID = uint32(uid)
+		s.Inode = inode
+		s.LocalPort = uint16(lport)
+		s.PeerPort = uint16(rport)
+		var rest []string
+		s.LocalAddrs, _, rest = parseAddrList(f[nfixed:])
+		if len(rest) > 0 && rest[0] == "<->" {
+			s.PeerAddrs, s.Primary, _ = parseAddrList(rest[1:])
+		}
+		out = append(out, s)
+		return nil
+	})
+	return out, err
+}
+
+// ParseRemoteAddrs parses the contents of /proc/net/sctp/remaddr.
+func ParseRemoteAddrs(r io.Reader) ([]RemoteAddr, error) {
+	// ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+	const nfixed = 8
+	var out []RemoteAddr
+	err := scanTable(r, func(f []string) error {
+		if len(f) < nfixed {
+			return errors.New("sctpdiag: short line in " + procRemoteAddrs)
+		}
+		addr, err0 := netip.ParseAddr(f[0])
+		id, err1 := strconv.ParseInt(f[1], 10, 32)
+		hb, err2 := strconv.Atoi(f[2])
+		rto, err3 := strconv.ParseUint(f[3], 10, 64)
+		maxRtx, err4 := strconv.Atoi(f[4])
+		errCount, err5 := strconv.Atoi(f[5])
+		state, err6 := strconv.Atoi(f[7])
+		if err := errors.Join(err0, err1, err2, err3, err4, err5, err6); err != nil {
+			return err
+		}
+		out = append(out, RemoteAddr{
+			Addr:       addr,
+			AssocID:    int32(id),
+			HBActive:   hb != 0,
+			RTO:        rto,
+			MaxPathRtx: maxRtx,
+			ErrorCount: errCount,
+			State:      state,
+		})
+		return nil
+	})
+	return out, err
+}
+
+// ParseSNMP parses the contents of /proc/net/sctp/snmp.
+func ParseSNMP(r io.Reader) (map[string]uint64, error) {
+	out := make(map[string]uint64)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		out[f[0]] = v
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// scanTable calls fn with the fields of every line of r after the
+// header line.
+func scanTable(r io.Reader, fn func([]string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	header := true
+	for sc.Scan() {
+		if header {
+			header = false
+			continue
+		}
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// parseAddrList parses the leading address fields of f, which the
+// kernel prefixes with '*' for the primary path. It returns the
+// addresses, the primary address and the fields that follow.
+func parseAddrList(f []string) (addrs []netip.Addr, primary netip.Addr, rest []string) {
+	for i, s := range f {
+		star := strings.HasPrefix(s, "*")
+		a, err := netip.ParseAddr(strings.TrimPrefix(s, "*"))
+		if err != nil {
+			return addrs, primary, f[i:]
+		}
+		if star {
+			primary = a
+		}
+		addrs = append(addrs, a)
+	}
+	return addrs, primary, nil
+}
+
+func endpointState(sst int) State {
+	switch sst {
+	case tcpListen:
+		return StateListen
+	case tcpEstablished:
+		return StateEstablished
+	}
+	return StateClosed
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctpdiag
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testEndpoints = ` ENDPT     SOCK   STY SST HBKT LPORT   UID INODE LADDRS
+ffff8f4a  ffff8f4b 2   10  29   9000      0 41237 127.0.0.1 127.0.0.2 
+ffff8f4c  ffff8f4d 2   7   12   40123  1000 41240 0000:0000:0000:0000:0000:0000:0000:0001 
+`
+
+const testAssocs = ` ASSOC     SOCK   STY SST ST HBKT ASSOC-ID TX_QUEUE RX_QUEUE UID INODE LPORT RPORT LADDRS <-> RADDRS HBINT INS OUTS MAXRT T1X T2X RTXC wmema wmemq sndbuf rcvbuf
+ffff8f50 ffff8f4a 2   10  3  1021    2        0        0       0 41237  9000 40123  127.0.0.1 *127.0.0.2 <-> *127.0.0.1 127.0.0.3 	   30000    10    10   10    0    0        0        1        0   212992   212992
+ffff8f51 ffff8f4c 2   1   4  1022    5      120       64    1000 41240 40123  9000  *127.0.0.1 <-> *127.0.0.1 	   30000    10    10   10    0    0        0        1        0   212992   212992
+`
+
+const testRemoteAddrs = `ADDR ASSOC_ID HB_ACT RTO MAX_PATH_RTX REM_ADDR_RTX START STATE
+127.0.0.1 2 1 250 5 0 0 2
+127.0.0.3 2 1 750 5 3 0 0
+`
+
+const testSNMP = `SctpCurrEstab                   	2
+SctpActiveEstabs                	5
+SctpPassiveEstabs               	1
+SctpAborteds                    	0
+`
+
+func TestParseEndpoints(t *testing.T) {
+	eps, err := ParseEndpoints(strings.NewReader(testEndpoints))
+	if err != nil {
+		t.Fatalf("ParseEndpoints error: %v", err)
+	}
+	if len(eps) != 2 {
+		t.Fatalf("ParseEndpoints len=%d; want 2", len(eps))
+	}
+	ep := eps[0]
+	if ep.Kind != Endpoint || ep.State != StateListen || ep.LocalPort != 9000 || ep.Inode != 41237 {
+		t.Errorf("ParseEndpoints[0]=%+v; want listening endpoint on 9000 inode 41237", ep)
+	}
+	want := []netip.Addr{netip.MustParseAddr("127.0.0.1"), netip.MustParseAddr("127.0.0.2")}
+	if !slices.Equal(ep.LocalAddrs, want) {
+		t.Errorf("ParseEndpoints[0].LocalAddrs=%v; want %v", ep.LocalAddrs, want)
+	}
+	if got := eps[1]; got.State != StateClosed || got.UID != 1000 || len(got.LocalAddrs) != 1 || !got.LocalAddrs[0].IsLoopback() {
+		t.Errorf("ParseEndpoints[1]=%+v; want closed IPv6 loopback endpoint owned by 1000", got)
+	}
+}
+
+func TestParseAssocs(t *testing.T) {
+	assocs, err := ParseAssocs(strings.NewReader(testAssocs))
+	if err != nil {
+		t.Fatalf("ParseAssocs error: %v", err)
+	}
+	if len(assocs) != 2 {
+		t.Fatalf("ParseAssocs len=%d; want 2", len(assocs))
+	}
+	a := assocs[0]
+	if a.Kind != Association || a.State != StateEstablished || a.AssocID != 2 {
+		t.Errorf("ParseAssocs[0]=%+v; want established association 2", a)
+	}
+	if a.LocalPort != 9000 || a.PeerPort != 40123 {
+		t.Errorf("ParseAssocs[0] ports=%d,%d; want 9000,40123", a.LocalPort, a.PeerPort)
+	}
+	if len(a.LocalAddrs) != 2 || len(a.PeerAddrs) != 2 {
+		t.Errorf("ParseAssocs[0] addrs=%v <-> %v; want two each", a.LocalAddrs, a.PeerAddrs)
+	}
+	if want := netip.MustParseAddr("127.0.0.1"); a.Primary != want {
+		t.Errorf("ParseAssocs[0].Primary=%v; want %v", a.Primary, want)
+	}
+	if b := assocs[1]; b.State != StateShutdownPending || b.TxQueue != 120 || b.RxQueue != 64 || b.UID != 1000 {
+		t.Errorf("ParseAssocs[1]=%+v; want SHUTDOWN_PENDING with queues 120/64 owned by 1000", b)
+	}
+}
+
+func TestParseRemoteAddrs(t *testing.T) {
+	ras, err := ParseRemoteAddrs(strings.NewReader(testRemoteAddrs))
+	if err != nil {
+		t.Fatalf("ParseRemoteAddrs error: %v", err)
+	}
+	if len(ras) != 2 {
+		t.Fatalf("ParseRemoteAddrs len=%d; want 2", len(ras))
+	}
+	if got := ras[1]; got.AssocID != 2 || !got.HBActive || got.RTO != 750 || got.ErrorCount != 3 || got.State != 0 {
+		t.Errorf("ParseRemoteAddrs[1]=%+v", got)
+	}
+}
+
+func TestParseSNMP(t *testing.T) {
+	m, err := ParseSNMP(strings.NewReader(testSNMP))
+	if err != nil {
+		t.Fatalf("ParseSNMP error: %v", err)
+	}
+	if m["SctpCurrEstab"] != 2 || m["SctpActiveEstabs"] != 5 || len(m) != 4 {
+		t.Errorf("ParseSNMP=%v", m)
+	}
+}
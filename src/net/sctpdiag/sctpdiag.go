@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctpdiag reports the SCTP endpoints and associations open on
+// the host, similar to the output of "ss -S".
+//
+// On Linux, [Sockets] queries the kernel through NETLINK_SOCK_DIAG
+// (inet_diag with IPPROTO_SCTP) and falls back to the files under
+// /proc/net/sctp when the sctp_diag module is not available.
+package sctpdiag
+
+import (
+	"errors"
+	"net/netip"
+	"time"
+)
+
+// Kind distinguishes listening or bound endpoints from associations.
+type Kind uint8
+
+const (
+	Endpoint Kind = iota
+	Association
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Endpoint:
+		return "endpoint"
+	case Association:
+		return "association"
+	}
+	return "unknown"
+}
+
+// State is the state of an SCTP association, as defined by RFC 9260.
+// Endpoints report either StateListen or StateClosed.
+type State uint8
+
+const (
+	StateClosed State = iota
+	StateCookieWait
+	StateCookieEchoed
+	StateEstablished
+	StateShutdownPending
+	StateShutdownSent
+	StateShutdownReceived
+	StateShutdownAckSent
+	StateListen
+)
+
+var stateNames = [...]string{
+	StateClosed:           "CLOSED",
+	StateCookieWait:       "COOKIE_WAIT",
+	StateCookieEchoed:     "COOKIE_ECHOED",
+	StateEstablished:      "ESTAB",
+	StateShutdownPending:  "SHUTDOWN_PENDING",
+	StateShutdownSent:     "SHUTDOWN_SENT",
+	StateShutdownReceived: "SHUTDOWN_RECEIVED",
+	StateShutdownAckSent:  "SHUTDOWN_ACK_SENT",
+	StateListen:           "LISTEN",
+}
+
+func (s State) String() string {
+	if int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return "UNKNOWN"
+}
+
+// Socket describes an SCTP endpoint or association.
+type Socket struct {
+	Kind  Kind
+	State State
+
+	// AssocID is the association identifier as seen by the owning
+	// socket. It is zero for endpoints.
+	AssocID int32
+
+	LocalPort  uint16
+	LocalAddrs []netip.Addr
+	PeerPort   uint16
+	PeerAddrs  []netip.Addr
+
+	// Primary is the peer address of the primary path, if known.
+	Primary netip.Addr
+
+	TxQueue uint32 // bytes queued for sending
+	RxQueue uint32 // bytes received but not yet read
+
+	// RTO is the retransmission timeout of the primary path.
+	// It is zero when the kernel does not report it in milliseconds,
+	// as is the case for /proc/net/sctp.
+	RTO time.Duration
+
+	UID   uint32
+	Inode uint64
+}
+
+// errUnsupported is returned by [NetlinkSockets] on platforms
+// without NETLINK_SOCK_DIAG.
+var errUnsupported = errors.New("sctpdiag: not supported on this platform")
+
+// Sockets returns every SCTP endpoint and association on the host.
+// It uses NETLINK_SOCK_DIAG when available and /proc/net/sctp otherwise.
+func Sockets() ([]Socket, error) {
+	socks, err := NetlinkSockets()
+	if err == nil {
+		return socks, nil
+	}
+	psocks, perr := ProcSockets()
+	if perr != nil {
+		return nil, errors.Join(err, perr)
+	}
+	return psocks, nil
+}
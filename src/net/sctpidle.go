@@ -0,0 +1,169 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"errors"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// SetAutoClose sets the idle time after which the kernel gracefully
+// shuts down an association of c, via SCTP_AUTOCLOSE. The duration is
+// rounded up to whole seconds. A duration of 0 disables auto-close.
+func (c *SCTPConn) SetAutoClose(d time.Duration) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if d < 0 {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: syscall.EINVAL}
+	}
+	sec := int((d + time.Second - 1) / time.Second)
+	if err := setAutoCloseSCTP(c.fd, sec); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// AutoClose returns the SCTP_AUTOCLOSE idle time, or 0 if auto-close
+// is disabled.
+func (c *SCTPConn) AutoClose() (time.Duration, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	sec, err := autoCloseSCTP(c.fd)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return time.Duration(sec) * time.Second, nil
+}
+
+// sctpIdleReaper closes associations that saw no traffic for timeout.
+type sctpIdleReaper struct {
+	timeout time.Duration
+	reaped  func(assocID int32)
+	last    map[int32]time.Time
+	timer   *time.Timer
+}
+
+// SetIdleTimeout gracefully shuts down associations of c that have not
+// sent or received data for d, and then calls reaped, if non-nil, with
+// the identifier of each association shut down. A duration of 0
+// disables the idle timeout.
+//
+// Unlike [SCTPConn.SetAutoClose], the timeout is enforced by c itself
+// and may be shorter than a second. Activity is observed on the
+// messages and notifications read by [SCTPConn.ReadFromSCTP] and
+// written by [SCTPConn.WriteToSCTP], so the caller must keep reading
+// from c; associations are also tracked from SCTP_ASSOC_CHANGE
+// notifications when [SCTPEventMask.Association] is subscribed.
+// SetIdleTimeout enables SCTP_RECVRCVINFO on the socket.
+func (c *SCTPConn) SetIdleTimeout(d time.Duration, reaped func(assocID int32)) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if d < 0 {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: syscall.EINVAL}
+	}
+	if d > 0 {
+		if err := setRecvRcvInfoSCTP(c.fd, true); err != nil {
+			return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.idle != nil {
+		c.idle.timer.Stop()
+		c.idle = nil
+	}
+	if d == 0 {
+		return nil
+	}
+	c.idle = &sctpIdleReaper{
+		timeout: d,
+		reaped:  reaped,
+		last:    make(map[int32]time.Time),
+	}
+	c.idle.timer = time.AfterFunc(d, c.reapIdle)
+	return nil
+}
+
+// touchAssoc records activity on the association identified by assocID.
+// The caller must hold c.mu.
+func (c *SCTPConn) touchAssoc(assocID int32) {
+	if c.idle == nil || assocID == 0 {
+		return
+	}
+	c.idle.last[assocID] = time.Now()
+}
+
+// forgetAssoc stops tracking the association identified by assocID.
+// The caller must hold c.mu.
+func (c *SCTPConn) forgetAssoc(assocID int32) {
+	if c.idle != nil {
+		delete(c.idle.last, assocID)
+	}
+}
+
+// stopIdle stops the idle timeout of c, so that its timer no longer
+// fires once c is closed.
+func (c *SCTPConn) stopIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.idle != nil {
+		c.idle.timer.Stop()
+		c.idle = nil
+	}
+}
+
+func (c *SCTPConn) reapIdle() {
+	c.mu.Lock()
+	r := c.idle
+	if r == nil {
+		c.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	next := r.timeout
+	var idle []int32
+	for id, t := range r.last {
+		if left := r.timeout - now.Sub(t); left > 0 {
+			next = min(next, left)
+			continue
+		}
+		idle = append(idle, id)
+	}
+	c.mu.Unlock()
+
+	var live []int32
+	for _, id := range idle {
+		if err := c.CloseAssoc(id); err != nil {
+			if errors.Is(err, ErrClosed) {
+				return
+			}
+			// Retry on the next run, unless the association has
+			// gone away in the meantime.
+			if live == nil {
+				live, _ = assocIDsSCTP(c.fd)
+			}
+			if slices.Contains(live, id) {
+				continue
+			}
+		} else if r.reaped != nil {
+			r.reaped(id)
+		}
+		c.mu.Lock()
+		delete(r.last, id)
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	if c.idle == r {
+		r.timer.Reset(next)
+	}
+	c.mu.Unlock()
+}
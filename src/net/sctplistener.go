@@ -0,0 +1,128 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"errors"
+	"syscall"
+	"time"
+)
+
+// SCTPListener accepts the associations of a one-to-many SCTP socket,
+// peeling each new association off into its own connected [SCTPConn].
+// Clients can use the connection returned by [DialSCTP] as is, or
+// branch its association off with [SCTPConn.PeelOff].
+//
+// HTTP is served over SCTP by the net/http/sctphttp package, which
+// maps each HTTP connection to a stream of an association instead.
+type SCTPListener struct {
+	c   *SCTPConn
+	buf []byte
+}
+
+// NewSCTPListener returns a listener accepting the associations of c,
+// which must be a socket returned by [ListenSCTP] or a similar
+// function. The listener takes over reading from c and subscribes it to
+// association change events only.
+func NewSCTPListener(c *SCTPConn) (*SCTPListener, error) {
+	if !c.ok() {
+		return nil, syscall.EINVAL
+	}
+	ln, err := newSCTPListener(c)
+	if err != nil {
+		return nil, &OpError{Op: "listen", Net: c.fd.net, Source: nil, Addr: c.fd.laddr, Err: err}
+	}
+	return ln, nil
+}
+
+func newSCTPListener(c *SCTPConn) (*SCTPListener, error) {
+	if err := subscribeSCTPEvents(c.fd, SCTPEventMask{Association: true}); err != nil {
+		return nil, err
+	}
+	return &SCTPListener{c: c, buf: make([]byte, 1024)}, nil
+}
+
+func (ln *SCTPListener) ok() bool { return ln != nil && ln.c.ok() }
+
+// AcceptSCTP waits for the next association to come up and returns it
+// peeled off as a new connection. Event subscriptions are cleared on
+// the returned connection, so Read returns user data only.
+//
+// Messages of associations not yet accepted are discarded.
+func (ln *SCTPListener) AcceptSCTP() (*SCTPConn, error) {
+	if !ln.ok() {
+		return nil, syscall.EINVAL
+	}
+	c, err := ln.accept()
+	if err != nil {
+		return nil, &OpError{Op: "accept", Net: ln.c.fd.net, Source: nil, Addr: ln.c.fd.laddr, Err: err}
+	}
+	return c, nil
+}
+
+func (ln *SCTPListener) accept() (*SCTPConn, error) {
+	for {
+		n, _, flags, _, _, err := ln.c.readFromSCTP(ln.buf)
+		if err != nil {
+			return nil, err
+		}
+		if flags&SCTPMsgNotification == 0 {
+			continue
+		}
+		nt, err := parseSCTPNotification(ln.buf[:n])
+		if err != nil {
+			continue
+		}
+		ac, ok := nt.(*SCTPAssocChangeEvent)
+		if !ok || ac.State != SCTPCommUp {
+			continue
+		}
+		fd, err := peelOffSCTP(ln.c.fd, ac.AssocID)
+		if errors.Is(err, syscall.EINVAL) {
+			// The association went away before it was accepted.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := subscribeSCTPEvents(fd, SCTPEventMask{}); err != nil {
+			fd.Close()
+			return nil, err
+		}
+		c := newSCTPConn(fd)
+		c.assocID = ac.AssocID
+		c.peeled = true
+		return c, nil
+	}
+}
+
+// Close stops listening on the SCTP address and closes the underlying
+// socket. Associations already accepted are not closed; associations
+// not yet accepted are aborted.
+func (ln *SCTPListener) Close() error {
+	if !ln.ok() {
+		return syscall.EINVAL
+	}
+	ln.c.stopIdle()
+	if err := ln.c.fd.Close(); err != nil {
+		return &OpError{Op: "close", Net: ln.c.fd.net, Source: nil, Addr: ln.c.fd.laddr, Err: err}
+	}
+	return nil
+}
+
+// Addr returns the listener's network address, an [*SCTPAddr].
+func (ln *SCTPListener) Addr() Addr { return ln.c.fd.laddr }
+
+// SetDeadline sets the deadline associated with the listener.
+// A zero time value disables the deadline.
+func (ln *SCTPListener) SetDeadline(t time.Time) error {
+	if !ln.ok() {
+		return syscall.EINVAL
+	}
+	return ln.c.fd.SetReadDeadline(t)
+}
+
+// Conn returns the one-to-many socket the listener accepts from.
+func (ln *SCTPListener) Conn() *SCTPConn { return ln.c }
@@ -0,0 +1,240 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"errors"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// sctpReader is the read loop behind [SCTPConn.Messages],
+// [SCTPConn.Notifications], [SCTPConn.Stream] and
+// [SCTPConn.AcceptStream]. It is started on first use and runs until
+// the socket fails or is closed.
+type sctpReader struct {
+	data      chan *SCTPMessage
+	events    chan SCTPNotification
+	accept    chan *SCTPStream // handles opened for AcceptStream
+	notify    atomic.Bool      // events are delivered once Notifications is called
+	messages  atomic.Bool      // Messages has been called
+	accepting atomic.Bool      // AcceptStream has been called
+	stop      chan struct{}    // closed by Close to release a blocked send
+	stopOnce  sync.Once
+	err       error // read error that ended the loop; set before data is closed
+	stopped   bool  // the loop has ended; guarded by the mu of the SCTPConn
+}
+
+// stopErr returns the error reported to stream handles after r has
+// stopped.
+func (r *sctpReader) stopErr(c *SCTPConn) error {
+	if r.err != nil {
+		return r.err
+	}
+	return &OpError{Op: "read", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: ErrClosed}
+}
+
+// Messages returns an iterator over the user messages received on c.
+// Each message is complete: partial reads are reassembled per
+// association and stream until the end of the message. A message
+// larger than 4 MiB, or one starting while 64 others are being
+// reassembled, stops the reader with an error.
+//
+// The first call to Messages, [SCTPConn.Notifications] or
+// [SCTPConn.Stream] enables SCTP_RECVRCVINFO and starts a goroutine
+// that reads from c until c fails or is closed; c must not otherwise
+// be read from after that. Messages received on a stream with an open
+// [SCTPStream] handle go to that handle instead, and messages on other
+// streams open new handles once [SCTPConn.AcceptStream] has been
+// called.
+// Notifications are separated from the data and are delivered only on
+// the channel returned by Notifications, or discarded if it was never
+// called. Stats, peer path tracking and the other state kept from
+// [SCTPConn.ReadFromSCTP] are maintained as usual.
+//
+// The reader does not buffer: a message is read only once the previous
+// one has been taken, so a slow consumer applies back-pressure to the
+// peer. Breaking out of a loop leaves the reader running, and a later
+// loop resumes with the next message. After [SCTPConn.Close] the
+// sequence ends without error. If reading fails for another reason,
+// such as an expired deadline, the sequence ends by yielding the error
+// and every later sequence yields only that error.
+func (c *SCTPConn) Messages() iter.Seq2[*SCTPMessage, error] {
+	return func(yield func(*SCTPMessage, error) bool) {
+		if !c.ok() {
+			yield(nil, syscall.EINVAL)
+			return
+		}
+		r := c.startReader(false)
+		r.messages.Store(true)
+		for m := range r.data {
+			if !yield(m, nil) {
+				return
+			}
+		}
+		if r.err != nil {
+			yield(nil, r.err)
+		}
+	}
+}
+
+// Notifications returns the channel on which the reader started by
+// [SCTPConn.Messages] delivers the event notifications read from c,
+// starting the reader if needed. Notifications read before the first
+// call are discarded. The channel is unbuffered and shares the reader
+// with Messages, so both must be drained for either to make progress.
+// It is closed when the reader stops.
+func (c *SCTPConn) Notifications() <-chan SCTPNotification {
+	if !c.ok() {
+		ch := make(chan SCTPNotification)
+		close(ch)
+		return ch
+	}
+	r := c.startReader(false)
+	r.notify.Store(true)
+	return r.events
+}
+
+// Close closes the connection. A reader started by [SCTPConn.Messages]
+// or [SCTPConn.Notifications] stops, ending its sequences and closing
+// its notification channel, and reads on its [SCTPStream] handles fail.
+// The idle timeout set by [SCTPConn.SetIdleTimeout] is stopped.
+func (c *SCTPConn) Close() error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	c.stopIdle()
+	c.mu.Lock()
+	r := c.reader
+	c.mu.Unlock()
+	if r != nil {
+		r.stopOnce.Do(func() { close(r.stop) })
+	}
+	return c.conn.Close()
+}
+
+// startReader returns the reader of c, starting it if needed. If
+// accept is set, messages on streams without a handle open new handles
+// from the first message read.
+func (c *SCTPConn) startReader(accept bool) *sctpReader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reader == nil {
+		c.reader = &sctpReader{
+			data:   make(chan *SCTPMessage),
+			events: make(chan SCTPNotification),
+			accept: make(chan *SCTPStream),
+			stop:   make(chan struct{}),
+		}
+		c.reader.accepting.Store(accept)
+		go c.readMessages(c.reader)
+	} else if accept {
+		c.reader.accepting.Store(true)
+	}
+	return c.reader
+}
+
+func (c *SCTPConn) readMessages(r *sctpReader) {
+	defer close(r.accept)
+	defer close(r.events)
+	defer close(r.data)
+	defer c.stopStreams(r)
+
+	if err := setRecvRcvInfoSCTP(c.fd, true); err != nil {
+		r.err = &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		return
+	}
+	buf := make([]byte, 64<<10)
+	partial := newSCTPReassembler(c, sctpMaxMessageBytes)
+	for {
+		n, _, flags, addr, info, err := c.ReadFromSCTP(buf)
+		if err != nil {
+			if !errors.Is(err, ErrClosed) {
+				r.err = err
+			}
+			return
+		}
+		if flags&SCTPMsgNotification != 0 {
+			nt, err := ParseSCTPNotification(buf[:n])
+			if err != nil {
+				continue
+			}
+			if ac, ok := nt.(*SCTPAssocChangeEvent); ok && ac.State != SCTPCommUp {
+				partial.forget(ac.AssocID)
+			}
+			c.observeStreams(nt)
+			if !r.notify.Load() {
+				continue
+			}
+			select {
+			case r.events <- nt:
+			case <-r.stop:
+				return
+			}
+			continue
+		}
+		if info == nil {
+			continue
+		}
+		data, err := partial.add(sctpStreamKey{info.AssocID, info.Stream}, buf[:n], flags)
+		if err != nil {
+			r.err = &OpError{Op: "read", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+			return
+		}
+		if data == nil {
+			continue
+		}
+
+		if st := c.streamFor(info.AssocID, info.Stream); st != nil {
+			if !st.deliver(data, addr, info.AssocID, r.stop) {
+				return
+			}
+			continue
+		}
+		if r.accepting.Load() {
+			st := c.openStream(r, info.AssocID, info.Stream, info.PPID)
+			st.deliver(data, addr, info.AssocID, r.stop)
+			select {
+			case r.accept <- st:
+			case <-r.stop:
+				return
+			}
+			continue
+		}
+		if !r.messages.Load() && c.hasStreams() {
+			continue
+		}
+		select {
+		case r.data <- &SCTPMessage{Data: data, Addr: addr, Info: *info}:
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// hasStreams reports whether c has open stream handles.
+func (c *SCTPConn) hasStreams() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.streams) > 0
+}
+
+// stopStreams records that r has stopped and fails the reads of the
+// stream handles of c.
+func (c *SCTPConn) stopStreams(r *sctpReader) {
+	c.mu.Lock()
+	r.stopped = true
+	streams := make([]*SCTPStream, 0, len(c.streams))
+	for _, s := range c.streams {
+		streams = append(streams, s)
+	}
+	c.mu.Unlock()
+	err := r.stopErr(c)
+	for _, s := range streams {
+		s.stop(err)
+	}
+}
@@ -56,6 +56,12 @@ func copySCTPAddrs(in []SCTPAddr) []SCTPAddr {
 }
 
 // ResolveSCTPMultiAddr resolves a list of SCTP endpoint addresses.
+//
+// A host name that resolves to several IP addresses contributes all
+// of them to the set, in resolver order and without duplicates. With
+// the "sctp" network, a set may mix IPv4 and IPv6 addresses; if the
+// system cannot create dual-stack sockets, only the addresses of the
+// family that [ResolveSCTPAddr] would pick for the host are kept.
 func ResolveSCTPMultiAddr(network string, addresses []string) (*SCTPMultiAddr, error) {
 	switch network {
 	case "sctp", "sctp4", "sctp6":
@@ -65,13 +71,13 @@ func ResolveSCTPMultiAddr(network string, addresses []string) (*SCTPMultiAddr, e
 	if len(addresses) == 0 {
 		return nil, errMissingAddress
 	}
-	out := &SCTPMultiAddr{Addrs: make([]SCTPAddr, len(addresses))}
-	for i, s := range addresses {
-		a, err := ResolveSCTPAddr(network, s)
+	out := &SCTPMultiAddr{Addrs: make([]SCTPAddr, 0, len(addresses))}
+	for _, s := range addresses {
+		addrs, err := DefaultResolver.internetAddrList(context.Background(), network, s)
 		if err != nil {
 			return nil, err
 		}
-		out.Addrs[i] = *a
+		out.Addrs = appendSCTPPeerSet(out.Addrs, network, s, addrs)
 	}
 	if err := validateSCTPMultiAddr(network, out.Addrs, false); err != nil {
 		return nil, err
@@ -79,25 +85,79 @@ func ResolveSCTPMultiAddr(network string, addresses []string) (*SCTPMultiAddr, e
 	return out, nil
 }
 
+// appendSCTPPeerSet appends to dst the SCTP addresses of addrs, the
+// resolution of address, that are not already in dst. Without
+// dual-stack support, only addresses of the family forResolve picks
+// are appended.
+func appendSCTPPeerSet(dst []SCTPAddr, network, address string, addrs addrList) []SCTPAddr {
+	mixed := network == "sctp" && supportsIPv4map()
+	want4 := isIPv4(addrs.forResolve(network, address))
+	for _, addr := range addrs {
+		a, ok := addr.(*SCTPAddr)
+		if !ok || !mixed && isIPv4(a) != want4 {
+			continue
+		}
+		dup := false
+		for i := range dst {
+			if dst[i].equal(a) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, *a)
+		}
+	}
+	return dst
+}
+
+// dialSCTPPeerSet dials one association to every address of addrs,
+// the resolution of address. It implements [Dialer.SetMultihomeSCTP].
+func (d *Dialer) dialSCTPPeerSet(ctx context.Context, network, address string, addrs addrList) (Conn, error) {
+	var laddr *SCTPMultiAddr
+	peers := appendSCTPPeerSet(nil, network, address, addrs)
+	if la, ok := d.LocalAddr.(*SCTPAddr); ok && la != nil {
+		laddr = &SCTPMultiAddr{Addrs: []SCTPAddr{*la}}
+		peers = sctpPeersForLocal(la, peers)
+	}
+	raddr := &SCTPMultiAddr{Addrs: peers}
+	c, err := dialSCTPMulti(ctx, d, network, laddr, raddr)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// sctpPeersForLocal returns the addresses of peers that a socket bound
+// to la can reach. A mixed set needs an AF_INET6 socket, which an IPv4
+// local address cannot have, so only the IPv4 peers are kept for it,
+// unless there are none.
+func sctpPeersForLocal(la *SCTPAddr, peers []SCTPAddr) []SCTPAddr {
+	if len(la.IP) == 0 || la.IP.To4() == nil || !mixedSCTPFamilies(peers) {
+		return peers
+	}
+	var v4 []SCTPAddr
+	for _, a := range peers {
+		if a.IP.To4() != nil {
+			v4 = append(v4, a)
+		}
+	}
+	if len(v4) == 0 {
+		return peers
+	}
+	return v4
+}
+
 func validateSCTPMultiAddr(network string, addrs []SCTPAddr, allowZeroPort bool) error {
 	if len(addrs) == 0 {
 		return errMissingAddress
 	}
-	var (
-		family int
-		port   int
-	)
+	var port int
 	for i := range addrs {
 		a := &addrs[i]
 		if err := validateSCTPAddrFamily(network, a); err != nil {
 			return err
 		}
-		af := a.family()
-		if i == 0 {
-			family = af
-		} else if af != family {
-			return &AddrError{Err: "mixed address family in sctp multi-address", Addr: a.String()}
-		}
 		switch {
 		case i == 0:
 			port = a.Port
@@ -113,6 +173,44 @@ func validateSCTPMultiAddr(network string, addrs []SCTPAddr, allowZeroPort bool)
 	return nil
 }
 
+// mixedSCTPFamilies reports whether the address sets together hold
+// both IPv4 and IPv6 addresses. Unspecified addresses are ignored.
+func mixedSCTPFamilies(sets ...[]SCTPAddr) bool {
+	var v4, v6 bool
+	for _, addrs := range sets {
+		for i := range addrs {
+			switch {
+			case len(addrs[i].IP) == 0:
+			case addrs[i].IP.To4() != nil:
+				v4 = true
+			default:
+				v6 = true
+			}
+		}
+	}
+	return v4 && v6
+}
+
+// dualStackSCTPAddrs returns a copy of addrs whose first address is
+// IPv6, so that the socket bound to it is an AF_INET6 socket that can
+// also carry the IPv4 addresses. If addrs is empty, the result is the
+// IPv6 unspecified address. It reports false if addrs holds no IPv6
+// address.
+func dualStackSCTPAddrs(addrs []SCTPAddr) ([]SCTPAddr, bool) {
+	if len(addrs) == 0 {
+		return []SCTPAddr{{IP: IPv6unspecified}}, true
+	}
+	for i := range addrs {
+		if len(addrs[i].IP) == IPv6len && addrs[i].IP.To4() == nil {
+			out := make([]SCTPAddr, 0, len(addrs))
+			out = append(out, addrs[i])
+			out = append(out, addrs[:i]...)
+			return append(out, addrs[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
 func validateSCTPAddrFamily(network string, a *SCTPAddr) error {
 	if a == nil {
 		return nil
@@ -130,9 +228,14 @@ func validateSCTPAddrFamily(network string, a *SCTPAddr) error {
 	return nil
 }
 
-// DialSCTPMulti acts like [DialSCTP] for multi-address local endpoints.
-// Remote multi-address setup requires connectx-style association setup and
-// is not supported by this v1 API.
+// DialSCTPMulti acts like [DialSCTP] for multi-address endpoints.
+// When raddr holds several addresses, they are passed to the kernel
+// together (sctp_connectx) as the peer addresses of one association.
+//
+// With the "sctp" network the local and remote sets may mix IPv4 and
+// IPv6 addresses. The association then uses an AF_INET6 socket, so
+// laddr, if not empty, must hold an IPv6 address; IPv4 peers are
+// reported as IPv4 addresses, not as IPv4-mapped IPv6 addresses.
 func DialSCTPMulti(network string, laddr, raddr *SCTPMultiAddr) (*SCTPConn, error) {
 	return dialSCTPMulti(context.Background(), nil, network, laddr, raddr)
 }
@@ -149,15 +252,24 @@ func dialSCTPMulti(ctx context.Context, dialer *Dialer, network string, laddr, r
 	if err := validateSCTPMultiAddr(network, raddr.Addrs, false); err != nil {
 		return nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: err}
 	}
+	var local []SCTPAddr
 	if laddr != nil && len(laddr.Addrs) > 0 {
 		if err := validateSCTPMultiAddr(network, laddr.Addrs, true); err != nil {
 			return nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: err}
 		}
+		local = laddr.Addrs
+	}
+	mixed := mixedSCTPFamilies(local, raddr.Addrs)
+	if mixed {
+		var ok bool
+		if local, ok = dualStackSCTPAddrs(local); !ok {
+			return nil, &OpError{Op: "dial", Net: network, Source: nil, Addr: nil, Err: &AddrError{Err: "mixed-family sctp multi-address needs an IPv6 local address", Addr: laddr.String()}}
+		}
 	}
 
 	var la, ra *SCTPAddr
-	if laddr != nil && len(laddr.Addrs) > 0 {
-		la = &laddr.Addrs[0]
+	if len(local) > 0 {
+		la = &local[0]
 	}
 	ra = &raddr.Addrs[0]
 
@@ -165,10 +277,16 @@ func dialSCTPMulti(ctx context.Context, dialer *Dialer, network string, laddr, r
 	if err != nil {
 		return nil, err
 	}
+	if mixed {
+		if err := setMappedV4SCTP(c.fd, false); err != nil {
+			c.Close()
+			return nil, &OpError{Op: "dial", Net: network, Source: la.opAddr(), Addr: ra.opAddr(), Err: err}
+		}
+	}
 	c.multiPeer = copySCTPAddrs(raddr.Addrs)
-	if laddr != nil && len(laddr.Addrs) > 1 {
-		extra := make([]SCTPAddr, len(laddr.Addrs)-1)
-		copy(extra, laddr.Addrs[1:])
+	if len(local) > 1 {
+		extra := make([]SCTPAddr, len(local)-1)
+		copy(extra, local[1:])
 		if laa, ok := c.LocalAddr().(*SCTPAddr); ok {
 			for i := range extra {
 				if extra[i].Port == 0 {
@@ -206,6 +324,8 @@ func dialSCTPMulti(ctx context.Context, dialer *Dialer, network string, laddr, r
 }
 
 // ListenSCTPMulti acts like [ListenSCTP] for multi-address local endpoints.
+// As with [DialSCTPMulti], the "sctp" network accepts a mix of IPv4
+// and IPv6 addresses.
 func ListenSCTPMulti(network string, laddr *SCTPMultiAddr) (*SCTPConn, error) {
 	return listenSCTPMulti(context.Background(), ListenConfig{}, network, laddr)
 }
@@ -216,26 +336,38 @@ func listenSCTPMulti(ctx context.Context, lc ListenConfig, network string, laddr
 	default:
 		return nil, &OpError{Op: "listen", Net: network, Source: nil, Addr: nil, Err: UnknownNetworkError(network)}
 	}
+	var local []SCTPAddr
 	if laddr != nil && len(laddr.Addrs) > 0 {
 		if err := validateSCTPMultiAddr(network, laddr.Addrs, true); err != nil {
 			return nil, &OpError{Op: "listen", Net: network, Source: nil, Addr: nil, Err: err}
 		}
+		local = laddr.Addrs
+	}
+	mixed := mixedSCTPFamilies(local)
+	if mixed {
+		local, _ = dualStackSCTPAddrs(local)
 	}
 
 	var la *SCTPAddr
-	if laddr != nil && len(laddr.Addrs) > 0 {
-		la = &laddr.Addrs[0]
+	if len(local) > 0 {
+		la = &local[0]
 	}
 	c, err := listenSCTP(ctx, lc, network, la)
 	if err != nil {
 		return nil, err
 	}
+	if mixed {
+		if err := setMappedV4SCTP(c.fd, false); err != nil {
+			c.Close()
+			return nil, &OpError{Op: "listen", Net: network, Source: nil, Addr: la.opAddr(), Err: err}
+		}
+	}
 	if base, ok := c.LocalAddr().(*SCTPAddr); ok && base != nil {
 		c.multiLocal = []SCTPAddr{*base}
 	}
-	if laddr != nil && len(laddr.Addrs) > 1 {
-		extra := make([]SCTPAddr, len(laddr.Addrs)-1)
-		copy(extra, laddr.Addrs[1:])
+	if len(local) > 1 {
+		extra := make([]SCTPAddr, len(local)-1)
+		copy(extra, local[1:])
 		if laa, ok := c.LocalAddr().(*SCTPAddr); ok {
 			for i := range extra {
 				if extra[i].Port == 0 {
@@ -282,12 +414,18 @@ func (c *SCTPConn) LocalAddrs() ([]SCTPAddr, error) {
 }
 
 // PeerAddrs returns the peer SCTP endpoint addresses for the association.
+// Addresses added or removed by the peer are reflected once their
+// SCTP_PEER_ADDR_CHANGE notifications have been read; see
+// [SCTPConn.PeerPaths].
 func (c *SCTPConn) PeerAddrs() ([]SCTPAddr, error) {
 	if !c.ok() {
 		return nil, syscall.EINVAL
 	}
-	if len(c.multiPeer) > 0 {
-		return copySCTPAddrs(c.multiPeer), nil
+	c.mu.Lock()
+	peers := copySCTPAddrs(c.multiPeer)
+	c.mu.Unlock()
+	if len(peers) > 0 {
+		return peers, nil
 	}
 	if a, ok := c.fd.raddr.(*SCTPAddr); ok && a != nil {
 		return []SCTPAddr{*a}, nil
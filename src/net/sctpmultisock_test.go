@@ -5,6 +5,7 @@
 package net
 
 import (
+	"context"
 	"errors"
 	"testing"
 )
@@ -24,3 +25,116 @@ func TestResolveSCTPMultiAddrMismatchedPorts(t *testing.T) {
 		t.Fatalf("ResolveSCTPMultiAddr error = %v; want AddrError", err)
 	}
 }
+
+// lookupSCTPPeers resolves "sctp-peers.test" to several addresses of
+// both families, including a duplicate.
+func lookupSCTPPeers(ctx context.Context, fn func(context.Context, string, string) ([]IPAddr, error), network, host string) ([]IPAddr, error) {
+	if host == "sctp-peers.test" {
+		return []IPAddr{
+			{IP: IPv4(127, 0, 0, 1)},
+			{IP: IPv6loopback},
+			{IP: IPv4(127, 0, 0, 2)},
+			{IP: IPv4(127, 0, 0, 1)},
+		}, nil
+	}
+	return fn(ctx, network, host)
+}
+
+func TestResolveSCTPMultiAddrHostname(t *testing.T) {
+	origTestHookLookupIP := testHookLookupIP
+	defer func() { testHookLookupIP = origTestHookLookupIP }()
+	testHookLookupIP = lookupSCTPPeers
+
+	mixed := "127.0.0.1:9000,127.0.0.2:9000"
+	if supportsIPv4map() {
+		mixed = "127.0.0.1:9000,[::1]:9000,127.0.0.2:9000"
+	}
+	tests := []struct {
+		network   string
+		addresses []string
+		want      string
+	}{
+		{"sctp", []string{"sctp-peers.test:9000"}, mixed},
+		{"sctp4", []string{"sctp-peers.test:9000", "127.0.0.3:9000"}, "127.0.0.1:9000,127.0.0.2:9000,127.0.0.3:9000"},
+		{"sctp4", []string{"127.0.0.2:9000", "sctp-peers.test:9000"}, "127.0.0.2:9000,127.0.0.1:9000"},
+		{"sctp6", []string{"sctp-peers.test:9000"}, "[::1]:9000"},
+	}
+	for _, tt := range tests {
+		a, err := ResolveSCTPMultiAddr(tt.network, tt.addresses)
+		if err != nil {
+			t.Errorf("ResolveSCTPMultiAddr(%q, %q): %v", tt.network, tt.addresses, err)
+			continue
+		}
+		if got := a.String(); got != tt.want {
+			t.Errorf("ResolveSCTPMultiAddr(%q, %q) = %s; want %s", tt.network, tt.addresses, got, tt.want)
+		}
+	}
+}
+
+func TestResolveSCTPMultiAddrMixedFamilies(t *testing.T) {
+	a, err := ResolveSCTPMultiAddr("sctp", []string{"127.0.0.1:9000", "[fe80::1%lo0]:9000"})
+	if err != nil {
+		t.Fatalf("ResolveSCTPMultiAddr(sctp) error: %v", err)
+	}
+	if got, want := a.String(), "127.0.0.1:9000,[fe80::1%lo0]:9000"; got != want {
+		t.Errorf("ResolveSCTPMultiAddr(sctp) = %s; want %s", got, want)
+	}
+	for _, network := range []string{"sctp4", "sctp6"} {
+		_, err := ResolveSCTPMultiAddr(network, []string{"127.0.0.1:9000", "[::1]:9000"})
+		var aerr *AddrError
+		if !errors.As(err, &aerr) {
+			t.Errorf("ResolveSCTPMultiAddr(%s) error = %v; want AddrError", network, err)
+		}
+	}
+}
+
+func TestDualStackSCTPAddrs(t *testing.T) {
+	in := []SCTPAddr{
+		{IP: IPv4(127, 0, 0, 1), Port: 1},
+		{IP: IPv4(127, 0, 0, 2), Port: 1},
+		{IP: ParseIP("fe80::1"), Port: 1, Zone: "eth1"},
+	}
+	if !mixedSCTPFamilies(in[:1], in[2:]) || mixedSCTPFamilies(in[:2]) {
+		t.Fatal("mixedSCTPFamilies misreports the address families")
+	}
+	out, ok := dualStackSCTPAddrs(in)
+	if !ok {
+		t.Fatal("dualStackSCTPAddrs reported no IPv6 address")
+	}
+	if got, want := (&SCTPMultiAddr{Addrs: out}).String(), "[fe80::1%eth1]:1,127.0.0.1:1,127.0.0.2:1"; got != want {
+		t.Errorf("dualStackSCTPAddrs = %s; want %s", got, want)
+	}
+	if in[0].IP.To4() == nil {
+		t.Error("dualStackSCTPAddrs modified its argument")
+	}
+	if _, ok := dualStackSCTPAddrs(in[:2]); ok {
+		t.Error("dualStackSCTPAddrs accepted an IPv4-only set")
+	}
+	if out, _ := dualStackSCTPAddrs(nil); len(out) != 1 || !out[0].IP.Equal(IPv6unspecified) {
+		t.Errorf("dualStackSCTPAddrs(nil) = %v; want [::]", out)
+	}
+}
+
+func TestSCTPPeersForLocal(t *testing.T) {
+	peers := []SCTPAddr{
+		{IP: IPv4(127, 0, 0, 1), Port: 1},
+		{IP: IPv6loopback, Port: 1},
+		{IP: IPv4(127, 0, 0, 2), Port: 1},
+	}
+	for _, tt := range []struct {
+		la    *SCTPAddr
+		peers []SCTPAddr
+		want  string
+	}{
+		{&SCTPAddr{IP: IPv4(127, 0, 0, 1)}, peers, "127.0.0.1:1,127.0.0.2:1"},
+		{&SCTPAddr{IP: IPv4zero}, peers, "127.0.0.1:1,127.0.0.2:1"},
+		{&SCTPAddr{IP: IPv6loopback}, peers, "127.0.0.1:1,[::1]:1,127.0.0.2:1"},
+		{&SCTPAddr{Port: 5}, peers, "127.0.0.1:1,[::1]:1,127.0.0.2:1"},
+		{&SCTPAddr{IP: IPv4(127, 0, 0, 1)}, peers[1:2], "[::1]:1"},
+	} {
+		got := (&SCTPMultiAddr{Addrs: sctpPeersForLocal(tt.la, tt.peers)}).String()
+		if got != tt.want {
+			t.Errorf("sctpPeersForLocal(%v) = %s; want %s", tt.la, got, tt.want)
+		}
+	}
+}
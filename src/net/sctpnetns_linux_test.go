@@ -0,0 +1,433 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+
+package net
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// setnsTrap is the setns(2) system call number. Package syscall does
+// not define SYS_SETNS on every architecture.
+var setnsTrap = map[string]uintptr{
+	"386":      346,
+	"amd64":    308,
+	"arm":      375,
+	"arm64":    268,
+	"loong64":  268,
+	"mips":     4344,
+	"mipsle":   4344,
+	"mips64":   5303,
+	"mips64le": 5303,
+	"ppc64":    350,
+	"ppc64le":  350,
+	"riscv64":  268,
+	"s390x":    339,
+}[runtime.GOARCH]
+
+// sctpTopology is a pair of network namespaces, side 0 and side 1,
+// joined by independent veth pairs. Path i addresses side 0 as
+// 10.201.i.1 and side 1 as 10.201.i.2, so every path is its own
+// subnet and can fail without affecting the others.
+type sctpTopology struct {
+	t     *testing.T
+	ip    string
+	ns    [2]string
+	paths int
+}
+
+// newSCTPTopology creates a topology with the given number of paths.
+// It skips the test when the caller lacks the privileges to create
+// network namespaces, and removes the namespaces when the test ends.
+func newSCTPTopology(t *testing.T, paths int) *sctpTopology {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("avoid external network")
+	}
+	if os.Getuid() != 0 {
+		t.Skip("must be root (CAP_NET_ADMIN)")
+	}
+	if setnsTrap == 0 {
+		t.Skipf("setns not known on %s", runtime.GOARCH)
+	}
+	xname, err := exec.LookPath("ip")
+	if err != nil {
+		t.Skipf("skipping test; no ip command: %v", err)
+	}
+	tp := &sctpTopology{t: t, ip: xname, paths: paths}
+	for side := range tp.ns {
+		tp.ns[side] = fmt.Sprintf("gosctp%d%c", os.Getpid(), 'a'+side)
+	}
+	if err := tp.run("netns", "add", tp.ns[0]); err != nil {
+		t.Skipf("cannot create network namespace: %v", err)
+	}
+	t.Cleanup(func() { tp.run("netns", "del", tp.ns[0]) })
+	if err := tp.run("netns", "add", tp.ns[1]); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { tp.run("netns", "del", tp.ns[1]) })
+
+	for side := range tp.ns {
+		if err := tp.run("-n", tp.ns[side], "link", "set", "lo", "up"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path := range paths {
+		if err := tp.run("link", "add", tp.link(0, path), "netns", tp.ns[0], "type", "veth",
+			"peer", "name", tp.link(1, path), "netns", tp.ns[1]); err != nil {
+			t.Skipf("cannot create veth pair: %v", err)
+		}
+		for side := range tp.ns {
+			if err := tp.run("-n", tp.ns[side], "addr", "add", tp.addr(side, path).String()+"/24", "dev", tp.link(side, path)); err != nil {
+				t.Fatal(err)
+			}
+			if err := tp.run("-n", tp.ns[side], "link", "set", tp.link(side, path), "up"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return tp
+}
+
+func (tp *sctpTopology) link(side, path int) string {
+	return fmt.Sprintf("veth%d%c", path, 'a'+side)
+}
+
+// addr returns the address of side on path.
+func (tp *sctpTopology) addr(side, path int) IP {
+	return IPv4(10, 201, byte(path), byte(side+1))
+}
+
+// path returns the path ip belongs to, or -1.
+func (tp *sctpTopology) path(ip IP) int {
+	for path := range tp.paths {
+		for side := range tp.ns {
+			if tp.addr(side, path).Equal(ip) {
+				return path
+			}
+		}
+	}
+	return -1
+}
+
+func (tp *sctpTopology) run(args ...string) error {
+	out, err := exec.Command(tp.ip, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ip %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// in calls f on a thread that has joined the namespace of side.
+// Sockets created by f stay in that namespace.
+func (tp *sctpTopology) in(side int, f func() error) error {
+	errc := make(chan error, 1)
+	go func() {
+		// The thread is never unlocked, so it exits with the goroutine
+		// instead of running other goroutines in the wrong namespace.
+		runtime.LockOSThread()
+		ns, err := os.Open(filepath.Join("/var/run/netns", tp.ns[side]))
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer ns.Close()
+		if _, _, e := syscall.RawSyscall(setnsTrap, ns.Fd(), syscall.CLONE_NEWNET, 0); e != 0 {
+			errc <- os.NewSyscallError("setns", e)
+			return
+		}
+		errc <- f()
+	}()
+	return <-errc
+}
+
+// setSysctl writes the net.sctp sysctls of both sides in order.
+func (tp *sctpTopology) setSysctl(kv ...string) {
+	tp.t.Helper()
+	for side := range tp.ns {
+		err := tp.in(side, func() error {
+			for i := 0; i+1 < len(kv); i += 2 {
+				if err := os.WriteFile(filepath.Join("/proc/sys/net/sctp", kv[i]), []byte(kv[i+1]), 0); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			tp.t.Fatal(err)
+		}
+	}
+}
+
+// blackhole silently drops all traffic on path in both directions.
+func (tp *sctpTopology) blackhole(path int) {
+	tp.t.Helper()
+	for side := range tp.ns {
+		if err := tp.run("-n", tp.ns[side], "route", "add", "blackhole", tp.addr(1-side, path).String()+"/32"); err != nil {
+			tp.t.Fatal(err)
+		}
+	}
+}
+
+// restore undoes blackhole.
+func (tp *sctpTopology) restore(path int) {
+	tp.t.Helper()
+	for side := range tp.ns {
+		if err := tp.run("-n", tp.ns[side], "route", "del", "blackhole", tp.addr(1-side, path).String()+"/32"); err != nil {
+			tp.t.Fatal(err)
+		}
+	}
+}
+
+// delay adds d of latency to both directions of path. It requires
+// the tc command and the netem queueing discipline.
+func (tp *sctpTopology) delay(path int, d time.Duration) error {
+	tc, err := exec.LookPath("tc")
+	if err != nil {
+		return err
+	}
+	for side := range tp.ns {
+		args := []string{"-n", tp.ns[side], "qdisc", "add", "dev", tp.link(side, path), "root", "netem", "delay", fmt.Sprintf("%dms", d.Milliseconds())}
+		if out, err := exec.Command(tc, args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("tc %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+		}
+	}
+	return nil
+}
+
+// fastFailover shortens the retransmission and heartbeat timers so
+// that a dead path is detected within a second.
+func (tp *sctpTopology) fastFailover() {
+	tp.setSysctl(
+		"rto_min", "50",
+		"rto_initial", "100",
+		"rto_max", "200",
+		"hb_interval", "100",
+		"path_max_retrans", "1",
+	)
+}
+
+// sctpFailoverPair is a multihomed association across a topology:
+// a listener on side 1 and a client on side 0, both bound to every
+// path.
+type sctpFailoverPair struct {
+	tp     *sctpTopology
+	srv    *SCTPConn
+	client *SCTPConn
+	recv   chan sctpFailoverMsg
+	events chan sctpPathEvent
+}
+
+type sctpFailoverMsg struct {
+	seq  int
+	from *SCTPAddr
+}
+
+type sctpPathEvent struct {
+	addr  IP
+	state SCTPPeerAddrState
+}
+
+func newSCTPFailoverPair(t *testing.T, tp *sctpTopology) *sctpFailoverPair {
+	t.Helper()
+	const port = 9899
+	la := &SCTPMultiAddr{}
+	ra := &SCTPMultiAddr{}
+	for path := range tp.paths {
+		la.Addrs = append(la.Addrs, SCTPAddr{IP: tp.addr(0, path)})
+		ra.Addrs = append(ra.Addrs, SCTPAddr{IP: tp.addr(1, path), Port: port})
+	}
+	p := &sctpFailoverPair{
+		tp:     tp,
+		recv:   make(chan sctpFailoverMsg, 1024),
+		events: make(chan sctpPathEvent, 64),
+	}
+	err := tp.in(1, func() (err error) {
+		p.srv, err = ListenSCTPMulti("sctp4", ra)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, syscall.EPROTONOSUPPORT) {
+			t.Skipf("kernel SCTP unavailable in namespace: %v", err)
+		}
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.srv.Close() })
+	err = tp.in(0, func() (err error) {
+		p.client, err = DialSCTPMulti("sctp4", la, ra)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.client.Close() })
+	if err := p.client.SubscribeEvents(SCTPEventMask{Address: true}); err != nil {
+		t.Fatal(err)
+	}
+	p.client.SetPeerPathHandler(func(_ int32, path SCTPPath) {
+		p.events <- sctpPathEvent{path.Addr.IP, path.State}
+	})
+
+	go func() {
+		defer close(p.recv)
+		buf := make([]byte, 64)
+		for {
+			n, _, flags, from, _, err := p.srv.ReadFromSCTP(buf)
+			if err != nil {
+				return
+			}
+			if flags&SCTPMsgNotification != 0 {
+				continue
+			}
+			var seq int
+			if _, err := fmt.Sscanf(string(buf[:n]), "msg %d", &seq); err != nil {
+				t.Errorf("unexpected message %q", buf[:n])
+				continue
+			}
+			p.recv <- sctpFailoverMsg{seq, from}
+		}
+	}()
+	go func() {
+		// Reading notifications drives the peer path tracking.
+		buf := make([]byte, 512)
+		for {
+			if _, _, _, _, _, err := p.client.ReadFromSCTP(buf); err != nil {
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// send writes messages first through last-1 on stream 0.
+func (p *sctpFailoverPair) send(t *testing.T, first, last int) {
+	t.Helper()
+	for seq := first; seq < last; seq++ {
+		if _, err := p.client.WriteToSCTP(fmt.Appendf(nil, "msg %d", seq), nil, &SCTPSndInfo{Stream: 0}); err != nil {
+			t.Fatalf("send %d: %v", seq, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// expect waits for messages first through last-1 in order and
+// returns the client path each one arrived on.
+func (p *sctpFailoverPair) expect(t *testing.T, first, last int) []int {
+	t.Helper()
+	var paths []int
+	timeout := time.After(10 * time.Second)
+	for seq := first; seq < last; seq++ {
+		select {
+		case m, ok := <-p.recv:
+			if !ok {
+				t.Fatalf("server stopped before message %d", seq)
+			}
+			if m.seq != seq {
+				t.Fatalf("got message %d, want %d", m.seq, seq)
+			}
+			paths = append(paths, p.tp.path(m.from.IP))
+		case <-timeout:
+			t.Fatalf("timed out waiting for message %d", seq)
+		}
+	}
+	return paths
+}
+
+// waitPathEvent waits for a peer address change to state for the
+// server address on path.
+func (p *sctpFailoverPair) waitPathEvent(t *testing.T, path int, state SCTPPeerAddrState) {
+	t.Helper()
+	want := p.tp.addr(1, path)
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case ev := <-p.events:
+			t.Logf("peer address %v state %d", ev.addr, ev.state)
+			if ev.addr.Equal(want) && ev.state == state {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no SCTP_PEER_ADDR_CHANGE to state %d for %v", state, want)
+		}
+	}
+}
+
+func TestSCTPMultihomeFailover(t *testing.T) {
+	requireSCTP(t)
+	tp := newSCTPTopology(t, 2)
+	tp.fastFailover()
+	p := newSCTPFailoverPair(t, tp)
+
+	// Learn which path carries the data before breaking it.
+	p.send(t, 0, 50)
+	paths := p.expect(t, 0, 50)
+	active := paths[len(paths)-1]
+	if active < 0 {
+		t.Fatalf("data arrived from outside the topology")
+	}
+	other := 1 - active
+
+	tp.blackhole(active)
+	p.send(t, 50, 100)
+	p.waitPathEvent(t, active, SCTPAddrUnreachable)
+	paths2, err := p.client.PeerPaths(p.client.assocID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pp := range paths2 {
+		want := SCTPAddrAvailable
+		if tp.path(pp.Addr.IP) == active {
+			want = SCTPAddrUnreachable
+		}
+		if pp.State != want {
+			t.Errorf("PeerPaths: %v in state %d; want %d", pp.Addr.IP, pp.State, want)
+		}
+	}
+	p.send(t, 100, 150)
+	paths = p.expect(t, 50, 150)
+	for i, path := range paths[50:] {
+		if path != other {
+			t.Fatalf("message %d arrived on path %d after failover, want %d", 100+i, path, other)
+		}
+	}
+
+	tp.restore(active)
+	p.waitPathEvent(t, active, SCTPAddrAvailable)
+	p.send(t, 150, 200)
+	p.expect(t, 150, 200)
+}
+
+func TestSCTPMultihomeDelayedPath(t *testing.T) {
+	requireSCTP(t)
+	tp := newSCTPTopology(t, 2)
+	tp.fastFailover()
+	p := newSCTPFailoverPair(t, tp)
+
+	p.send(t, 0, 20)
+	paths := p.expect(t, 0, 20)
+	active := paths[len(paths)-1]
+	if active < 0 {
+		t.Fatalf("data arrived from outside the topology")
+	}
+
+	// A delay well above rto_max forces retransmissions on the other
+	// path; the receiver must still see every message exactly once,
+	// in order.
+	if err := tp.delay(active, 500*time.Millisecond); err != nil {
+		t.Skipf("cannot delay path: %v", err)
+	}
+	p.send(t, 20, 120)
+	p.expect(t, 20, 120)
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+// SCTPMsgNotification is set in the flags returned by
+// [SCTPConn.ReadFromSCTP] when the message read is an event
+// notification rather than user data. Such messages can be decoded
+// with [ParseSCTPNotification].
+const SCTPMsgNotification = 0x8000
+
+// SCTPMsgEOR is set in the flags returned by [SCTPConn.ReadFromSCTP]
+// on the read that completes a message or notification. A message
+// larger than the buffer is returned by several reads, and only the
+// last one has SCTPMsgEOR set.
+const SCTPMsgEOR = sctpMsgEOR
+
+// SCTPNotification is an SCTP event notification decoded by
+// [ParseSCTPNotification]. Its dynamic type is one of the SCTP*Event
+// types of this package, or [*SCTPUnknownEvent] for notifications
+// that are not decoded.
+type SCTPNotification interface {
+	sctpNotification()
+}
+
+// SCTPAssocChangeState is the state reported by an [SCTPAssocChangeEvent].
+type SCTPAssocChangeState uint16
+
+const (
+	SCTPCommUp SCTPAssocChangeState = iota
+	SCTPCommLost
+	SCTPRestart
+	SCTPShutdownComplete
+	SCTPCantStartAssoc
+)
+
+// SCTPAssocChangeEvent reports that an association has started or
+// ended (SCTP_ASSOC_CHANGE).
+type SCTPAssocChangeEvent struct {
+	State           SCTPAssocChangeState
+	Error           uint16
+	OutboundStreams uint16
+	InboundStreams  uint16
+	AssocID         int32
+	Info            []byte // peer ABORT cause or supported features, if any
+}
+
+// SCTPPeerAddrState is the state reported by an
+// [SCTPPeerAddrChangeEvent].
+type SCTPPeerAddrState int32
+
+const (
+	SCTPAddrAvailable SCTPPeerAddrState = iota
+	SCTPAddrUnreachable
+	SCTPAddrRemoved
+	SCTPAddrAdded
+	SCTPAddrMadePrimary
+	SCTPAddrConfirmed
+	SCTPAddrPotentiallyFailed
+)
+
+// SCTPPeerAddrChangeEvent reports a change of a peer address of an
+// association (SCTP_PEER_ADDR_CHANGE).
+type SCTPPeerAddrChangeEvent struct {
+	Addr    SCTPAddr
+	State   SCTPPeerAddrState
+	Error   int32
+	AssocID int32
+}
+
+// SCTPAdaptationEvent carries the adaptation layer indication sent by
+// the peer during association setup (SCTP_ADAPTATION_INDICATION).
+type SCTPAdaptationEvent struct {
+	Indication uint32
+	AssocID    int32
+}
+
+// SCTPSenderDryEvent reports that the association has no user data
+// queued or awaiting acknowledgement (SCTP_SENDER_DRY_EVENT).
+type SCTPSenderDryEvent struct {
+	AssocID int32
+}
+
+// SCTPSendFailedEvent returns a message the kernel could not deliver
+// (SCTP_SEND_FAILED_EVENT, or SCTP_SEND_FAILED on older kernels). It
+// is only reported when [SCTPEventMask.SendFailure] is subscribed.
+// The message can be sent again with [SCTPConn.Resend].
+type SCTPSendFailedEvent struct {
+	// Info holds the send parameters of the message, including the
+	// Context given to [SCTPConn.WriteToSCTP], and the association
+	// it was sent on.
+	Info SCTPSndInfo
+
+	// Payload is the undelivered message, or the part of it that had
+	// not been sent.
+	Payload []byte
+
+	// Error is the cause of the failure, usually an SCTP error cause
+	// code.
+	Error uint32
+
+	// Unsent reports whether no part of the message reached the
+	// network. Otherwise the peer may have received some of it.
+	Unsent bool
+}
+
+// Flags of an [SCTPStreamResetEvent].
+const (
+	SCTPStreamResetIncoming = 0x0001 // the peer reset its outgoing streams
+	SCTPStreamResetOutgoing = 0x0002 // the local outgoing streams were reset
+	SCTPStreamResetDenied   = 0x0004 // the peer denied the request
+	SCTPStreamResetFailed   = 0x0008 // the request failed
+)
+
+// SCTPStreamResetEvent reports the outcome of an RFC 6525 stream reset
+// (SCTP_STREAM_RESET_EVENT). An empty Streams means every stream.
+type SCTPStreamResetEvent struct {
+	Flags   uint16
+	AssocID int32
+	Streams []uint16
+}
+
+// SCTPUnknownEvent is a notification whose type is not decoded.
+type SCTPUnknownEvent struct {
+	Type  uint16
+	Flags uint16
+	Data  []byte // the complete notification, including its header
+}
+
+func (*SCTPAssocChangeEvent) sctpNotification()    {}
+func (*SCTPPeerAddrChangeEvent) sctpNotification() {}
+func (*SCTPAdaptationEvent) sctpNotification()     {}
+func (*SCTPSenderDryEvent) sctpNotification()      {}
+func (*SCTPStreamResetEvent) sctpNotification()    {}
+func (*SCTPSendFailedEvent) sctpNotification()     {}
+func (*SCTPUnknownEvent) sctpNotification()        {}
+
+// ParseSCTPNotification decodes a notification read by
+// [SCTPConn.ReadFromSCTP] with [SCTPMsgNotification] set in flags.
+func ParseSCTPNotification(b []byte) (SCTPNotification, error) {
+	return parseSCTPNotification(b)
+}
+
+// observeNotification records association state carried by a
+// notification read from c.
+func (c *SCTPConn) observeNotification(b []byte) {
+	n, err := parseSCTPNotification(b)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	var (
+		handler func(int32, SCTPPath)
+		changed SCTPPath
+		assocID int32
+	)
+	switch n := n.(type) {
+	case *SCTPAdaptationEvent:
+		if c.adaptation == nil {
+			c.adaptation = make(map[int32]uint32)
+		}
+		c.adaptation[n.AssocID] = n.Indication
+	case *SCTPAssocChangeEvent:
+		switch n.State {
+		case SCTPCommUp, SCTPRestart:
+			c.touchAssoc(n.AssocID)
+			c.loadPaths(n.AssocID)
+		case SCTPCommLost, SCTPShutdownComplete, SCTPCantStartAssoc:
+			delete(c.adaptation, n.AssocID)
+			delete(c.paths, c.pathKey(n.AssocID))
+			c.forgetAssoc(n.AssocID)
+		}
+	case *SCTPPeerAddrChangeEvent:
+		changed = c.updatePath(n)
+		handler, assocID = c.pathHandler, n.AssocID
+	}
+	c.mu.Unlock()
+	if handler != nil {
+		handler(assocID, changed)
+	}
+}
+
+// PeerAdaptationLayer returns the adaptation layer indication the peer
+// of the association identified by assocID sent during setup. The
+// indication is recorded from notifications returned by
+// [SCTPConn.ReadFromSCTP], so it is only available when
+// [SCTPEventMask.Adaptation] is subscribed and the notification has
+// been read. The boolean reports whether an indication was recorded.
+func (c *SCTPConn) PeerAdaptationLayer(assocID int32) (uint32, bool) {
+	if !c.ok() {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ind, ok := c.adaptation[assocID]
+	return ind, ok
+}
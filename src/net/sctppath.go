@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"slices"
+	"syscall"
+)
+
+// SCTPPath is a peer address of an association and its last known
+// state, as tracked by [SCTPConn.PeerPaths].
+type SCTPPath struct {
+	Addr SCTPAddr
+
+	// State is the last reachability state reported for the
+	// address: SCTPAddrAvailable, SCTPAddrUnreachable,
+	// SCTPAddrAdded, SCTPAddrConfirmed or
+	// SCTPAddrPotentiallyFailed. When the path was removed, as
+	// reported to a handler set by [SCTPConn.SetPeerPathHandler],
+	// it is SCTPAddrRemoved.
+	State SCTPPeerAddrState
+
+	// Primary reports whether the address is the primary path of
+	// the association.
+	Primary bool
+}
+
+// PeerPaths returns the peer addresses of the association identified
+// by assocID and their last known states. On a socket returned by
+// [SCTPConn.PeelOff] or [SCTPListener.AcceptSCTP], assocID is ignored.
+//
+// The paths are read from the kernel when the association comes up
+// and then kept current from SCTP_PEER_ADDR_CHANGE notifications
+// returned by [SCTPConn.ReadFromSCTP], so the states are only
+// maintained while [SCTPEventMask.Address] is subscribed and
+// notifications are being read. Nothing reads them in the background:
+// the application must itself read from c, with ReadFromSCTP or a
+// method built on it such as [SCTPConn.Read] or [SCTPConn.Messages].
+// Otherwise PeerPaths returns the paths as they were when the
+// association came up or when PeerPaths was first called.
+func (c *SCTPConn) PeerPaths(assocID int32) ([]SCTPPath, error) {
+	if !c.ok() {
+		return nil, syscall.EINVAL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := c.pathKey(assocID)
+	if _, ok := c.paths[key]; !ok {
+		if err := c.loadPaths(assocID); err != nil {
+			return nil, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+	}
+	return slices.Clone(c.paths[key]), nil
+}
+
+// SetPeerPathHandler sets f to be called with the new state of a peer
+// path each time an SCTP_PEER_ADDR_CHANGE notification is read from
+// c. f runs on the goroutine that called [SCTPConn.ReadFromSCTP] and
+// should not block. As with [SCTPConn.PeerPaths], f is only called
+// while the application reads from c. A nil f removes the handler.
+func (c *SCTPConn) SetPeerPathHandler(f func(assocID int32, p SCTPPath)) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	c.mu.Lock()
+	c.pathHandler = f
+	c.mu.Unlock()
+	return nil
+}
+
+// pathKey returns the key of assocID in c.paths. A peeled-off socket
+// holds a single association, whose notifications may carry an
+// identifier the caller does not know, so it is always keyed as 0.
+func (c *SCTPConn) pathKey(assocID int32) int32 {
+	if c.peeled {
+		return 0
+	}
+	return assocID
+}
+
+// loadPaths replaces the tracked paths of assocID with the kernel's
+// view. c.mu must be held.
+func (c *SCTPConn) loadPaths(assocID int32) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	id := assocID
+	if c.peeled {
+		id = 0
+	}
+	paths, err := peerPathsSCTP(c.fd, id)
+	if err != nil {
+		return err
+	}
+	if c.paths == nil {
+		c.paths = make(map[int32][]SCTPPath)
+	}
+	c.paths[c.pathKey(assocID)] = paths
+	return nil
+}
+
+// updatePath applies n to the tracked paths and returns the changed
+// path. c.mu must be held.
+func (c *SCTPConn) updatePath(n *SCTPPeerAddrChangeEvent) SCTPPath {
+	if c.paths == nil {
+		c.paths = make(map[int32][]SCTPPath)
+	}
+	key := c.pathKey(n.AssocID)
+	paths := c.paths[key]
+	i := slices.IndexFunc(paths, func(p SCTPPath) bool { return p.Addr.equal(&n.Addr) })
+	if i < 0 && n.State != SCTPAddrRemoved {
+		paths = append(paths, SCTPPath{Addr: n.Addr, State: SCTPAddrAvailable})
+		i = len(paths) - 1
+	}
+	var p SCTPPath
+	switch n.State {
+	case SCTPAddrRemoved:
+		p = SCTPPath{Addr: n.Addr, State: SCTPAddrRemoved}
+		if i >= 0 {
+			paths = slices.Delete(paths, i, i+1)
+		}
+		c.multiPeer = slices.DeleteFunc(c.multiPeer, func(a SCTPAddr) bool { return a.equal(&n.Addr) })
+	case SCTPAddrMadePrimary:
+		for j := range paths {
+			paths[j].Primary = j == i
+		}
+		p = paths[i]
+	default:
+		paths[i].State = n.State
+		p = paths[i]
+		if n.State == SCTPAddrAdded && len(c.multiPeer) > 0 &&
+			!slices.ContainsFunc(c.multiPeer, func(a SCTPAddr) bool { return a.equal(&n.Addr) }) {
+			c.multiPeer = append(c.multiPeer, n.Addr)
+		}
+	}
+	c.paths[key] = paths
+	return p
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"errors"
+	"io"
+	"syscall"
+)
+
+// sctpMaxSendChunk bounds the messages written by SendFrom, so that the
+// copy buffer stays small when the send buffer is large.
+const sctpMaxSendChunk = 64 << 10
+
+// SendFrom reads r until EOF and sends the data as consecutive
+// messages with the stream, PPID, flags and association of info,
+// which may be nil. Each message is no larger than
+// [SCTPConn.MaxMessageSize] of the association, so a full send buffer
+// makes SendFrom wait for room rather than fail. If the limit shrinks
+// while sending, the remaining data is split further. SendFrom returns
+// the number of bytes sent.
+//
+// SendFrom is the bulk counterpart of [SCTPConn.WriteToSCTP]. SCTPConn
+// does not implement [io.ReaderFrom], as its ReadFrom method is that of
+// [PacketConn]; an [SCTPStream] handle does.
+func (c *SCTPConn) SendFrom(r io.Reader, info *SCTPSndInfo) (int64, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	var (
+		assocID int32
+		addr    *SCTPAddr
+	)
+	if info != nil && info.AssocID != 0 {
+		assocID = info.AssocID
+		addr = c.assocPeerAddr(assocID)
+	}
+	return sendChunks(r, c.sendChunkSize(assocID), func(b []byte) (int, error) {
+		return c.WriteToSCTP(b, addr, info)
+	})
+}
+
+// sendChunkSize returns the size of the messages SendFrom writes on the
+// association identified by assocID.
+func (c *SCTPConn) sendChunkSize(assocID int32) int {
+	if n, err := maxMessageSizeSCTP(c.fd, assocID); err == nil && n > 0 {
+		return min(n, sctpMaxSendChunk)
+	}
+	return sctpMaxSendChunk
+}
+
+// sendChunks reads r until EOF and passes the data of each read to
+// write in messages of at most size bytes.
+func sendChunks(r io.Reader, size int, write func([]byte) (int, error)) (int64, error) {
+	buf := make([]byte, size)
+	var sent int64
+	for {
+		n, rerr := r.Read(buf)
+		for p := buf[:n]; len(p) > 0; {
+			m := min(len(p), size)
+			w, err := write(p[:m])
+			var serr *SCTPMessageSizeError
+			if errors.As(err, &serr) && serr.Max > 0 && serr.Max < m {
+				size = serr.Max
+				continue
+			}
+			sent += int64(w)
+			if err != nil {
+				return sent, err
+			}
+			p = p[m:]
+		}
+		if rerr == io.EOF {
+			return sent, nil
+		}
+		if rerr != nil {
+			return sent, rerr
+		}
+	}
+}
@@ -0,0 +1,548 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// ErrSCTPServerClosed is returned by [SCTPServer.Serve] and
+// [SCTPServer.ListenAndServe] after a call to [SCTPServer.Shutdown]
+// or [SCTPServer.Close].
+var ErrSCTPServerClosed = errors.New("net: SCTP server closed")
+
+// SCTPMessage is a complete user message received by an [SCTPServer]
+// or returned by [SCTPConn.Messages].
+type SCTPMessage struct {
+	Data []byte
+	Addr *SCTPAddr   // source address of the message
+	Info SCTPRcvInfo // stream, PPID and association of the message
+}
+
+// SCTPResponseWriter is used by an [SCTPHandler] to reply to the
+// association a message arrived on.
+type SCTPResponseWriter interface {
+	// Write sends b on the stream and with the PPID of the message
+	// being handled.
+	Write(b []byte) (int, error)
+
+	// WriteMessage sends b with the given send parameters. The
+	// association is always that of the message being handled.
+	WriteMessage(b []byte, info SCTPSndInfo) (int, error)
+
+	// Conn returns the socket the message was read from.
+	Conn() *SCTPConn
+}
+
+// An SCTPHandler responds to an SCTP message.
+//
+// Calls to ServeSCTP for messages of the same association and stream
+// are made one at a time, in arrival order. Calls for different
+// associations or streams, and for unordered messages, may run
+// concurrently.
+type SCTPHandler interface {
+	ServeSCTP(w SCTPResponseWriter, m *SCTPMessage)
+}
+
+// The SCTPHandlerFunc type is an adapter to allow the use of ordinary
+// functions as SCTP handlers.
+type SCTPHandlerFunc func(w SCTPResponseWriter, m *SCTPMessage)
+
+// ServeSCTP calls f(w, m).
+func (f SCTPHandlerFunc) ServeSCTP(w SCTPResponseWriter, m *SCTPMessage) {
+	f(w, m)
+}
+
+// SCTPServeMux routes SCTP messages to handlers by payload protocol
+// identifier. Messages with an unregistered PPID go to the default
+// handler, or are dropped if there is none.
+type SCTPServeMux struct {
+	mu       sync.RWMutex
+	handlers map[uint32]SCTPHandler
+	def      SCTPHandler
+}
+
+// NewSCTPServeMux allocates and returns a new [SCTPServeMux].
+func NewSCTPServeMux() *SCTPServeMux {
+	return &SCTPServeMux{handlers: make(map[uint32]SCTPHandler)}
+}
+
+// Handle registers h for messages carrying ppid.
+func (mux *SCTPServeMux) Handle(ppid uint32, h SCTPHandler) {
+	if h == nil {
+		panic("net: nil SCTP handler")
+	}
+	mux.mu.Lock()
+	mux.handlers[ppid] = h
+	mux.mu.Unlock()
+}
+
+// HandleFunc registers f for messages carrying ppid.
+func (mux *SCTPServeMux) HandleFunc(ppid uint32, f func(SCTPResponseWriter, *SCTPMessage)) {
+	mux.Handle(ppid, SCTPHandlerFunc(f))
+}
+
+// HandleDefault registers h for messages whose PPID has no handler.
+func (mux *SCTPServeMux) HandleDefault(h SCTPHandler) {
+	mux.mu.Lock()
+	mux.def = h
+	mux.mu.Unlock()
+}
+
+// ServeSCTP dispatches m to the handler registered for its PPID.
+func (mux *SCTPServeMux) ServeSCTP(w SCTPResponseWriter, m *SCTPMessage) {
+	mux.mu.RLock()
+	h, ok := mux.handlers[m.Info.PPID]
+	if !ok {
+		h = mux.def
+	}
+	mux.mu.RUnlock()
+	if h != nil {
+		h.ServeSCTP(w, m)
+	}
+}
+
+// SCTPServer reads messages from one-to-many SCTP sockets and
+// dispatches them to a handler.
+//
+// The server keeps one queue per association and stream, so messages
+// of a stream are handled in order while different streams and
+// associations are handled concurrently. The messages waiting in the
+// queues or being handled are limited by MaxPending; past the limit
+// the server stops reading until a handler returns.
+type SCTPServer struct {
+	// Handler handles user messages. It is typically an
+	// [*SCTPServeMux].
+	Handler SCTPHandler
+
+	// Notify, if non-nil, is called from the read loop for every
+	// event notification read from the socket. Notifications are
+	// only delivered for events subscribed with
+	// [SCTPConn.SubscribeEvents].
+	Notify func(c *SCTPConn, n SCTPNotification)
+
+	// ReadBufferSize is the size of the buffer used for each read.
+	// Larger messages are reassembled from several reads. If zero,
+	// 64 KiB is used.
+	ReadBufferSize int
+
+	// MaxPending limits the messages of one socket that have been
+	// read but whose handler has not returned yet, whether queued
+	// behind an earlier message of their stream or running, ordered
+	// or unordered. When the limit is reached, the server stops
+	// reading from the socket until a handler returns, leaving
+	// further messages in the receive buffer of the socket so that
+	// flow control slows the peers down. If zero, 256 is used.
+	MaxPending int
+
+	// MaxMessageBytes limits the size of a message reassembled from
+	// several reads. At most 64 messages are reassembled at a time on
+	// a socket. A message exceeding either limit is dropped and its
+	// association is aborted. If zero, 4 MiB is used.
+	MaxMessageBytes int
+
+	inShutdown atomic.Bool
+	reading    sync.WaitGroup // read loops of Serve still running
+	active     sync.WaitGroup // messages read and not yet handled
+	serving    sync.WaitGroup // calls to Serve that have not closed their socket
+
+	mu        sync.Mutex
+	conns     map[*SCTPConn]struct{}
+	stop      chan struct{} // closed when the read loops must stop
+	done      chan struct{} // closed when read loops may close their sockets
+	closeOnce sync.Once
+}
+
+type sctpStreamKey struct {
+	assocID int32
+	stream  uint16
+}
+
+// sctpStreamQueue holds the pending messages of one association and
+// stream. A queue is present in the dispatch map while its worker runs.
+type sctpStreamQueue struct {
+	pending []*SCTPMessage
+}
+
+// Limits of the messages reassembled from partial reads: the bytes of
+// one message, by default, and the messages reassembled at a time on
+// one socket.
+const (
+	sctpMaxMessageBytes = 4 << 20
+	sctpMaxPartial      = 64
+)
+
+// errSCTPMessageTooLarge is reported for a message whose reassembly
+// exceeds the limits of an sctpReassembler.
+var errSCTPMessageTooLarge = errors.New("sctp: reassembled message exceeds limits")
+
+// An sctpReassembler joins the partial reads of messages, per
+// association and stream. The peers control the size of the messages
+// and how many are interleaved, so both are bounded. Past the limits,
+// the association of the message is dropped: its partial messages are
+// discarded, and so is anything read from it afterwards, so that the
+// remaining parts of a message are not taken for a complete one.
+type sctpReassembler struct {
+	max     int                      // bytes of one message
+	assocs  func() ([]int32, error)  // associations of the socket
+	partial map[sctpStreamKey][]byte // messages being reassembled
+	dropped [sctpMaxPartial]int32    // associations dropped, in a ring
+	ndrop   int                      // associations ever dropped
+}
+
+func newSCTPReassembler(c *SCTPConn, max int) *sctpReassembler {
+	return &sctpReassembler{
+		max:     max,
+		assocs:  func() ([]int32, error) { return assocIDsSCTP(c.fd) },
+		partial: make(map[sctpStreamKey][]byte),
+	}
+}
+
+// add records b, read from the stream key with the given flags. It
+// returns the message once complete, or nil if more reads are needed.
+// It returns errSCTPMessageTooLarge on the read that exceeds the
+// limits, after which the association of key is dropped.
+func (r *sctpReassembler) add(key sctpStreamKey, b []byte, flags int) ([]byte, error) {
+	if r.isDropped(key.assocID) {
+		return nil, nil
+	}
+	eor := flags&SCTPMsgEOR != 0
+	p, ok := r.partial[key]
+	if !ok {
+		if eor {
+			return append([]byte(nil), b...), nil
+		}
+		if len(r.partial) >= sctpMaxPartial && !r.prune() {
+			r.drop(key.assocID)
+			return nil, errSCTPMessageTooLarge
+		}
+	}
+	if len(p)+len(b) > r.max {
+		r.drop(key.assocID)
+		return nil, errSCTPMessageTooLarge
+	}
+	p = append(p, b...)
+	if !eor {
+		r.partial[key] = p
+		return nil, nil
+	}
+	delete(r.partial, key)
+	return p, nil
+}
+
+// forget discards the partial messages of an association that ended.
+func (r *sctpReassembler) forget(assocID int32) {
+	for k := range r.partial {
+		if k.assocID == assocID {
+			delete(r.partial, k)
+		}
+	}
+}
+
+func (r *sctpReassembler) drop(assocID int32) {
+	r.forget(assocID)
+	r.dropped[r.ndrop%len(r.dropped)] = assocID
+	r.ndrop++
+}
+
+func (r *sctpReassembler) isDropped(assocID int32) bool {
+	for i := range min(r.ndrop, len(r.dropped)) {
+		if r.dropped[i] == assocID {
+			return true
+		}
+	}
+	return false
+}
+
+// prune discards the partial messages of associations that no longer
+// exist, which ended without a notification being read, and reports
+// whether room was made.
+func (r *sctpReassembler) prune() bool {
+	ids, err := r.assocs()
+	if err != nil {
+		return false
+	}
+	for k := range r.partial {
+		if !slices.Contains(ids, k.assocID) {
+			delete(r.partial, k)
+		}
+	}
+	return len(r.partial) < sctpMaxPartial
+}
+
+// errSCTPServerStopped ends a read loop that was waiting for a handler
+// when the server shut down.
+var errSCTPServerStopped = errors.New("sctp server stopped")
+
+// ListenAndServe listens on laddr and then calls [SCTPServer.Serve]
+// with the resulting socket.
+func (s *SCTPServer) ListenAndServe(network string, laddr *SCTPAddr) error {
+	if s.shuttingDown() {
+		return ErrSCTPServerClosed
+	}
+	c, err := ListenSCTP(network, laddr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(c)
+}
+
+// Serve reads messages from c and dispatches them until c fails or
+// the server is shut down. Serve enables SCTP_RECVRCVINFO on c and
+// closes c before returning.
+//
+// Serve always returns a non-nil error. After [SCTPServer.Shutdown]
+// or [SCTPServer.Close], the returned error is [ErrSCTPServerClosed].
+func (s *SCTPServer) Serve(c *SCTPConn) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setRecvRcvInfoSCTP(c.fd, true); err != nil {
+		c.Close()
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	stop, ok := s.trackConn(c)
+	if !ok {
+		c.Close()
+		return ErrSCTPServerClosed
+	}
+	defer s.serving.Done()
+	defer s.untrackConn(c)
+
+	err := s.readLoop(c, stop)
+	s.reading.Done()
+	if s.shuttingDown() {
+		s.waitClose(c)
+		return ErrSCTPServerClosed
+	}
+	c.Close()
+	return err
+}
+
+// readLoop reads messages from c and dispatches them until reading
+// fails or stop is closed.
+func (s *SCTPServer) readLoop(c *SCTPConn, stop <-chan struct{}) error {
+	size := s.ReadBufferSize
+	if size <= 0 {
+		size = 64 << 10
+	}
+	max := s.MaxPending
+	if max <= 0 {
+		max = 256
+	}
+	maxBytes := s.MaxMessageBytes
+	if maxBytes <= 0 {
+		maxBytes = sctpMaxMessageBytes
+	}
+	buf := make([]byte, size)
+	var (
+		mu      sync.Mutex
+		queues  = make(map[sctpStreamKey]*sctpStreamQueue)
+		partial = newSCTPReassembler(c, maxBytes)
+		pending = make(chan struct{}, max)
+	)
+	// handled ends the handling of a message.
+	handled := func() {
+		<-pending
+		s.active.Done()
+	}
+	for {
+		n, _, flags, addr, info, err := c.ReadFromSCTP(buf)
+		if err != nil {
+			return err
+		}
+		if flags&SCTPMsgNotification != 0 {
+			if nt, err := ParseSCTPNotification(buf[:n]); err == nil {
+				if ac, ok := nt.(*SCTPAssocChangeEvent); ok && ac.State != SCTPCommUp {
+					partial.forget(ac.AssocID)
+				}
+				if s.Notify != nil {
+					s.Notify(c, nt)
+				}
+			}
+			continue
+		}
+		if info == nil {
+			continue
+		}
+		key := sctpStreamKey{info.AssocID, info.Stream}
+		data, err := partial.add(key, buf[:n], flags)
+		if err != nil {
+			c.AbortAssoc(info.AssocID)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+
+		select {
+		case pending <- struct{}{}:
+		case <-stop:
+			return errSCTPServerStopped
+		}
+		m := &SCTPMessage{Data: data, Addr: addr, Info: *info}
+		s.active.Add(1)
+		if info.Flags&SCTPUnordered != 0 {
+			go func() {
+				defer handled()
+				s.serveMessage(c, m)
+			}()
+			continue
+		}
+		mu.Lock()
+		if q, ok := queues[key]; ok {
+			q.pending = append(q.pending, m)
+			mu.Unlock()
+			continue
+		}
+		q := &sctpStreamQueue{pending: []*SCTPMessage{m}}
+		queues[key] = q
+		mu.Unlock()
+		go func() {
+			for {
+				mu.Lock()
+				if len(q.pending) == 0 {
+					delete(queues, key)
+					mu.Unlock()
+					return
+				}
+				m := q.pending[0]
+				q.pending[0] = nil
+				q.pending = q.pending[1:]
+				mu.Unlock()
+				s.serveMessage(c, m)
+				handled()
+			}
+		}()
+	}
+}
+
+func (s *SCTPServer) serveMessage(c *SCTPConn, m *SCTPMessage) {
+	if s.Handler == nil {
+		return
+	}
+	s.Handler.ServeSCTP(&sctpResponse{c: c, m: m}, m)
+}
+
+// Shutdown gracefully shuts down the server: it stops reading from
+// every socket passed to [SCTPServer.Serve], waits for the handlers
+// already running or queued to return, and then closes the sockets.
+// If ctx expires first, Shutdown closes the sockets and returns the
+// context's error. In both cases the sockets are closed when Shutdown
+// returns.
+func (s *SCTPServer) Shutdown(ctx context.Context) error {
+	done := s.stopReading()
+
+	idle := make(chan struct{})
+	go func() {
+		// No message is dispatched once the read loops have
+		// stopped, so active only decreases from then on.
+		s.reading.Wait()
+		s.active.Wait()
+		close(idle)
+	}()
+	var err error
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	s.closeOnce.Do(func() { close(done) })
+	s.serving.Wait()
+	return err
+}
+
+// Close immediately closes every socket passed to [SCTPServer.Serve].
+// Handlers still running may fail to write their replies.
+func (s *SCTPServer) Close() error {
+	done := s.stopReading()
+	s.closeOnce.Do(func() { close(done) })
+	s.serving.Wait()
+	return nil
+}
+
+func (s *SCTPServer) shuttingDown() bool {
+	return s.inShutdown.Load()
+}
+
+// channels returns the stop and done channels of the server, creating
+// them if needed. s.mu must be held.
+func (s *SCTPServer) channels() (stop, done chan struct{}) {
+	if s.stop == nil {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+	}
+	return s.stop, s.done
+}
+
+// stopReading interrupts the read loops of the server. The sockets
+// are closed by their read loops once the returned channel is closed.
+func (s *SCTPServer) stopReading() chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stop, done := s.channels()
+	if !s.inShutdown.Swap(true) {
+		close(stop)
+	}
+	for c := range s.conns {
+		c.SetReadDeadline(aLongTimeAgo)
+	}
+	return done
+}
+
+// waitClose closes c once the shutdown in progress allows it.
+func (s *SCTPServer) waitClose(c *SCTPConn) {
+	s.mu.Lock()
+	_, done := s.channels()
+	s.mu.Unlock()
+	<-done
+	c.Close()
+}
+
+// trackConn registers c with the server and returns the channel
+// closed when its read loop must stop. It reports false if the
+// server is shutting down.
+func (s *SCTPServer) trackConn(c *SCTPConn) (<-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown() {
+		return nil, false
+	}
+	if s.conns == nil {
+		s.conns = make(map[*SCTPConn]struct{})
+	}
+	s.conns[c] = struct{}{}
+	s.reading.Add(1)
+	s.serving.Add(1)
+	stop, _ := s.channels()
+	return stop, true
+}
+
+func (s *SCTPServer) untrackConn(c *SCTPConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, c)
+}
+
+type sctpResponse struct {
+	c *SCTPConn
+	m *SCTPMessage
+}
+
+func (r *sctpResponse) Write(b []byte) (int, error) {
+	return r.WriteMessage(b, SCTPSndInfo{Stream: r.m.Info.Stream, PPID: r.m.Info.PPID})
+}
+
+func (r *sctpResponse) WriteMessage(b []byte, info SCTPSndInfo) (int, error) {
+	info.AssocID = r.m.Info.AssocID
+	return r.c.WriteToSCTP(b, r.m.Addr, &info)
+}
+
+func (r *sctpResponse) Conn() *SCTPConn { return r.c }
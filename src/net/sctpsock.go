@@ -7,8 +7,12 @@ package net
 import (
 	"context"
 	"internal/strconv"
+	"io"
 	"net/netip"
+	"slices"
+	"sync"
 	"syscall"
+	"time"
 )
 
 // SCTPAddr represents the address of an SCTP end point.
@@ -60,6 +64,11 @@ func (a *SCTPAddr) opAddr() Addr {
 	return a
 }
 
+// equal reports whether a and b are the same endpoint address.
+func (a *SCTPAddr) equal(b *SCTPAddr) bool {
+	return a.Port == b.Port && a.Zone == b.Zone && a.IP.Equal(b.IP)
+}
+
 // ResolveSCTPAddr returns an address of SCTP end point.
 //
 // The network must be an SCTP network name.
@@ -122,6 +131,16 @@ type SCTPRcvInfo struct {
 	AssocID int32
 }
 
+// Flags for [SCTPSndInfo.Flags].
+const (
+	SCTPUnordered       = 0x1   // deliver the message unordered
+	SCTPAddrOver        = 0x2   // send to the given address rather than the primary path
+	SCTPAbort           = 0x4   // abort the association
+	SCTPSackImmediately = 0x8   // ask the peer to acknowledge without delay
+	SCTPSendAll         = 0x40  // send to every association of the socket
+	SCTPEOF             = 0x200 // gracefully shut down the association
+)
+
 // SCTPEventMask configures SCTP event subscriptions via SCTP_EVENT.
 type SCTPEventMask struct {
 	DataIO          bool
@@ -137,6 +156,59 @@ type SCTPEventMask struct {
 	StreamReset     bool
 }
 
+// SCTPFeatures selects the SCTP protocol extensions an endpoint offers
+// when setting up associations, or reports the extensions negotiated
+// with a peer.
+type SCTPFeatures struct {
+	ECN                bool // explicit congestion notification, SCTP_ECN_SUPPORTED
+	ASCONF             bool // dynamic address reconfiguration (RFC 5061), SCTP_ASCONF_SUPPORTED
+	Auth               bool // authenticated chunks (RFC 4895), SCTP_AUTH_SUPPORTED
+	StreamReconfig     bool // stream reconfiguration (RFC 6525), SCTP_RECONFIG_SUPPORTED
+	PartialReliability bool // PR-SCTP (RFC 3758), SCTP_PR_SUPPORTED
+	Interleaving       bool // I-DATA message interleaving (RFC 8260), SCTP_INTERLEAVING_SUPPORTED
+}
+
+// SCTPAssocStats reports per-association counters maintained by the
+// kernel, as returned by SCTP_GET_ASSOC_STATS.
+//
+// MaxRTO and MaxRTOAddr describe the largest retransmission timeout
+// observed since the previous query and the peer transport it was
+// observed on; the kernel resets them on every query.
+type SCTPAssocStats struct {
+	MaxRTO     time.Duration
+	MaxRTOAddr *SCTPAddr
+
+	InSACKs            uint64 // SACKs received
+	OutSACKs           uint64 // SACKs sent
+	InPackets          uint64 // packets received
+	OutPackets         uint64 // packets sent
+	RetransChunks      uint64 // retransmitted chunks
+	OutOfSeqTSNs       uint64 // TSNs received beyond the next expected
+	DupChunks          uint64 // duplicate chunks received
+	GapAcks            uint64 // gap acknowledgements received
+	InUnorderedChunks  uint64 // unordered data chunks received
+	OutUnorderedChunks uint64 // unordered data chunks sent
+	InOrderedChunks    uint64 // ordered data chunks received
+	OutOrderedChunks   uint64 // ordered data chunks sent
+	InControlChunks    uint64 // control chunks received
+	OutControlChunks   uint64 // control chunks sent
+}
+
+// SCTPMessageSizeError is returned, wrapped in an [OpError], when a
+// message is larger than the association currently accepts.
+// It unwraps to the platform's "message too long" error, which is
+// [syscall.EMSGSIZE] on Linux.
+type SCTPMessageSizeError struct {
+	Len int // length of the rejected message
+	Max int // maximum message size, as reported by [SCTPConn.MaxMessageSize]
+}
+
+func (e *SCTPMessageSizeError) Error() string {
+	return "sctp: message of " + strconv.Itoa(e.Len) + " bytes exceeds maximum of " + strconv.Itoa(e.Max) + " bytes"
+}
+
+func (e *SCTPMessageSizeError) Unwrap() error { return errSCTPMessageSize }
+
 // SCTPConn is an implementation of the [Conn] and [PacketConn] interfaces
 // for SCTP network connections.
 type SCTPConn struct {
@@ -144,6 +216,23 @@ type SCTPConn struct {
 	multiLocal []SCTPAddr
 	multiPeer  []SCTPAddr
 	assocID    int32
+	peeled     bool // one-to-one socket created by PeelOff
+
+	mu           sync.Mutex
+	adaptation   map[int32]uint32 // peer adaptation indications by association
+	idle         *sctpIdleReaper
+	paths        map[int32][]SCTPPath // peer paths by association
+	pathHandler  func(assocID int32, p SCTPPath)
+	stats        sctpCounters
+	reader       *sctpReader // started by Messages, Notifications or Stream
+	streams      map[sctpStreamKey]*SCTPStream
+	dialAssoc    int32     // association read by Read on a dialed conn, once known
+	rcvInfoOn    bool      // Read enabled SCTP_RECVRCVINFO
+	readEOF      bool      // the association read by Read has ended
+	wdeadline    time.Time // write deadline set on c
+	sendDeadline time.Time // earlier deadline of a send waiting in the poller
+	readNote     []byte    // notification being read in parts by Read
+	readNoteDrop bool      // readNote exceeded sctpMaxDialNotification
 }
 
 func newSCTPConn(fd *netFD) *SCTPConn { return &SCTPConn{conn: conn{fd}} }
@@ -166,6 +255,193 @@ func (c *SCTPConn) ReadFrom(b []byte) (n int, addr Addr, err error) {
 	return n, saddr, err
 }
 
+// dialed reports whether c is a one-to-many socket returned by
+// [DialSCTP] or a similar function, which sends to its remote address
+// without being connected.
+func (c *SCTPConn) dialed() bool { return !connectedSCTP(c.fd) && c.fd.raddr != nil }
+
+// Read implements the [Conn] Read method.
+//
+// On a connection returned by [DialSCTP] or a similar function, Read
+// behaves as on a connected socket: it returns the data of the dialed
+// association only, skipping event notifications and messages of other
+// associations, and returns [io.EOF] once the association has ended
+// (which is only seen when [SCTPEventMask.Association] is subscribed).
+// A message larger than b is returned by consecutive reads; a
+// notification larger than b is reassembled internally. Read
+// enables SCTP_RECVRCVINFO to tell associations apart.
+func (c *SCTPConn) Read(b []byte) (int, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	if !c.dialed() {
+		return c.conn.Read(b)
+	}
+	c.mu.Lock()
+	eof, on := c.readEOF, c.rcvInfoOn
+	if c.dialAssoc == 0 {
+		c.dialAssoc = c.assocID
+	}
+	c.mu.Unlock()
+	if eof {
+		return 0, io.EOF
+	}
+	if !on {
+		if err := setRecvRcvInfoSCTP(c.fd, true); err != nil {
+			return 0, &OpError{Op: "read", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+		c.mu.Lock()
+		c.rcvInfoOn = true
+		c.mu.Unlock()
+	}
+	for {
+		n, _, flags, addr, info, err := c.ReadFromSCTP(b)
+		if err != nil {
+			return n, err
+		}
+		if flags&SCTPMsgNotification != 0 {
+			if nt := c.dialNotification(b[:n], flags); nt != nil && c.dialEnded(nt) {
+				return 0, io.EOF
+			}
+			continue
+		}
+		if c.ownMessage(addr, info) {
+			return n, nil
+		}
+	}
+}
+
+// ownMessage reports whether a message read by Read on a dialed conn
+// belongs to the dialed association. The association is identified by
+// the first message received from a dialed address.
+func (c *SCTPConn) ownMessage(addr *SCTPAddr, info *SCTPRcvInfo) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if info != nil && c.dialAssoc != 0 {
+		return info.AssocID == c.dialAssoc
+	}
+	if addr == nil {
+		return false
+	}
+	own := slices.ContainsFunc(c.multiPeer, func(a SCTPAddr) bool { return a.equal(addr) })
+	if ra, ok := c.fd.raddr.(*SCTPAddr); ok && ra.equal(addr) {
+		own = true
+	}
+	if own && info != nil {
+		c.dialAssoc = info.AssocID
+	}
+	return own
+}
+
+// sctpMaxDialNotification bounds the notifications reassembled by Read
+// on a dialed conn. Larger ones, such as send failures returning big
+// messages, are dropped: they do not end the association.
+const sctpMaxDialNotification = 64 << 10
+
+// dialNotification reassembles the notifications read by Read on a
+// dialed conn, which may be read in parts when b is small, and returns
+// a complete one, or nil.
+func (c *SCTPConn) dialNotification(b []byte, flags int) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if flags&SCTPMsgEOR != 0 && c.readNote == nil {
+		return b
+	}
+	if c.readNote == nil {
+		c.readNote = make([]byte, 0, len(b))
+	}
+	if len(c.readNote)+len(b) > sctpMaxDialNotification {
+		c.readNoteDrop = true
+	} else if !c.readNoteDrop {
+		c.readNote = append(c.readNote, b...)
+	}
+	if flags&SCTPMsgEOR == 0 {
+		return nil
+	}
+	nt := c.readNote
+	if c.readNoteDrop {
+		nt = nil
+	}
+	c.readNote, c.readNoteDrop = nil, false
+	return nt
+}
+
+// dialEnded reports whether the notification b ends the association
+// read by Read on a dialed conn, and records it. Before the association
+// is identified, any association that ends is taken to be the dialed
+// one: a dialed socket does not accept associations, so others only
+// exist if the caller sent to other addresses.
+func (c *SCTPConn) dialEnded(b []byte) bool {
+	n, err := parseSCTPNotification(b)
+	if err != nil {
+		return false
+	}
+	ac, ok := n.(*SCTPAssocChangeEvent)
+	if !ok || ac.State == SCTPCommUp || ac.State == SCTPRestart {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dialAssoc != 0 && ac.AssocID != c.dialAssoc {
+		return false
+	}
+	c.readEOF = true
+	return true
+}
+
+// SetDeadline implements the [Conn] SetDeadline method.
+func (c *SCTPConn) SetDeadline(t time.Time) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.conn.SetDeadline(t); err != nil {
+		return err
+	}
+	c.wdeadline = t
+	return c.keepSendDeadline()
+}
+
+// SetWriteDeadline implements the [Conn] SetWriteDeadline method.
+func (c *SCTPConn) SetWriteDeadline(t time.Time) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.conn.SetWriteDeadline(t); err != nil {
+		return err
+	}
+	c.wdeadline = t
+	return c.keepSendDeadline()
+}
+
+// keepSendDeadline sets the write deadline of the socket back to the
+// deadline of a send waiting in the poller if that is earlier than the
+// write deadline of c. c.mu must be held.
+func (c *SCTPConn) keepSendDeadline() error {
+	if c.sendDeadline.IsZero() || !c.wdeadline.IsZero() && !c.sendDeadline.Before(c.wdeadline) {
+		return nil
+	}
+	return c.conn.SetWriteDeadline(c.sendDeadline)
+}
+
+// Write implements the [Conn] Write method.
+//
+// On a connection returned by [DialSCTP] or a similar function, Write
+// sends b as one message to the remote address with the default send
+// parameters, as WriteToSCTP(b, nil, nil) does.
+func (c *SCTPConn) Write(b []byte) (int, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	if !c.dialed() {
+		return c.conn.Write(b)
+	}
+	return c.WriteToSCTP(b, nil, nil)
+}
+
 // ReadFromSCTP reads an SCTP message and returns SCTP metadata when available.
 func (c *SCTPConn) ReadFromSCTP(b []byte) (n int, oobn int, flags int, addr *SCTPAddr, info *SCTPRcvInfo, err error) {
 	if !c.ok() {
@@ -174,6 +450,16 @@ func (c *SCTPConn) ReadFromSCTP(b []byte) (n int, oobn int, flags int, addr *SCT
 	n, oobn, flags, addr, info, err = c.readFromSCTP(b)
 	if err != nil {
 		err = &OpError{Op: "read", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		return
+	}
+	c.mu.Lock()
+	c.countRead(b, n, flags, info)
+	if flags&SCTPMsgNotification == 0 && info != nil {
+		c.touchAssoc(info.AssocID)
+	}
+	c.mu.Unlock()
+	if flags&SCTPMsgNotification != 0 {
+		c.observeNotification(b[:n])
 	}
 	return
 }
@@ -187,7 +473,10 @@ func (c *SCTPConn) WriteTo(b []byte, addr Addr) (int, error) {
 	if !ok {
 		return 0, &OpError{Op: "write", Net: c.fd.net, Source: c.fd.laddr, Addr: addr, Err: syscall.EINVAL}
 	}
-	n, err := c.writeToSCTP(b, a, nil)
+	n, err := c.writeToSCTP(b, a, nil, time.Time{})
+	c.mu.Lock()
+	c.countWrite(n, nil, err)
+	c.mu.Unlock()
 	if err != nil {
 		err = &OpError{Op: "write", Net: c.fd.net, Source: c.fd.laddr, Addr: a.opAddr(), Err: err}
 	}
@@ -199,13 +488,57 @@ func (c *SCTPConn) WriteToSCTP(b []byte, addr *SCTPAddr, info *SCTPSndInfo) (int
 	if !c.ok() {
 		return 0, syscall.EINVAL
 	}
-	n, err := c.writeToSCTP(b, addr, info)
+	return c.sendSCTP(b, addr, info, time.Time{})
+}
+
+// sendSCTP implements WriteToSCTP. If deadline is not zero and the
+// send buffer is full, sendSCTP gives up once deadline passes, without
+// sending b.
+func (c *SCTPConn) sendSCTP(b []byte, addr *SCTPAddr, info *SCTPSndInfo, deadline time.Time) (int, error) {
+	n, err := c.writeToSCTP(b, addr, info, deadline)
+	c.mu.Lock()
+	c.countWrite(n, info, err)
+	if err == nil && info != nil {
+		c.touchAssoc(info.AssocID)
+	}
+	c.mu.Unlock()
 	if err != nil {
 		err = &OpError{Op: "write", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: err}
 	}
 	return n, err
 }
 
+// Resend sends the message reported by ev again, with the same stream,
+// PPID, flags and context, on the association identified by assocID.
+// If assocID is 0, the message is sent to addr instead, which on a
+// one-to-many socket sets up a new association when none exists. A
+// relay can use Resend to move messages off an association that
+// failed.
+func (c *SCTPConn) Resend(ev *SCTPSendFailedEvent, assocID int32, addr *SCTPAddr) (int, error) {
+	if !c.ok() || ev == nil {
+		return 0, syscall.EINVAL
+	}
+	info := ev.Info
+	info.AssocID = assocID
+	if addr == nil && assocID != 0 {
+		addr = c.assocPeerAddr(assocID)
+	}
+	return c.WriteToSCTP(ev.Payload, addr, &info)
+}
+
+// assocPeerAddr returns an address to send to on the association
+// identified by assocID, or nil if the socket needs none.
+func (c *SCTPConn) assocPeerAddr(assocID int32) *SCTPAddr {
+	if connectedSCTP(c.fd) || c.fd.raddr != nil {
+		return nil
+	}
+	peers, err := peerAddrsSCTP(c.fd, assocID)
+	if err != nil || len(peers) == 0 {
+		return nil
+	}
+	return &peers[0]
+}
+
 // SetNoDelay controls SCTP_NODELAY.
 func (c *SCTPConn) SetNoDelay(noDelay bool) error {
 	if !c.ok() {
@@ -228,6 +561,19 @@ func (c *SCTPConn) SetInitOptions(opts SCTPInitOptions) error {
 	return nil
 }
 
+// SetRecvRcvInfo controls SCTP_RECVRCVINFO, which makes
+// [SCTPConn.ReadFromSCTP] return the stream, PPID and association of
+// each message. It is also enabled by [SCTPConn.SetInitOptions].
+func (c *SCTPConn) SetRecvRcvInfo(on bool) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setRecvRcvInfoSCTP(c.fd, on); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
 // SubscribeEvents configures SCTP event subscriptions.
 func (c *SCTPConn) SubscribeEvents(mask SCTPEventMask) error {
 	if !c.ok() {
@@ -239,6 +585,441 @@ func (c *SCTPConn) SubscribeEvents(mask SCTPEventMask) error {
 	return nil
 }
 
+// SetLinger sets the behavior of Close on an SCTP socket whose
+// associations still have data waiting to be sent or acknowledged.
+//
+// If sec < 0 (the default), Close shuts down every association
+// gracefully in the background.
+//
+// If sec == 0, Close aborts every association, discarding any unsent
+// or unacknowledged data, and the peers receive an ABORT.
+//
+// If sec > 0, Close blocks for up to sec seconds while the
+// associations shut down gracefully, then aborts them.
+func (c *SCTPConn) SetLinger(sec int) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setLinger(c.fd, sec); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// CloseWrite gracefully shuts down the association of a dialed
+// connection: the data already queued is delivered, then the
+// association is closed with SHUTDOWN. The socket itself stays open,
+// so pending data and notifications can still be read.
+func (c *SCTPConn) CloseWrite() error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if c.peeled {
+		// One-to-one sockets reject SCTP_EOF; shutdown(2) sends
+		// SHUTDOWN instead.
+		if err := c.fd.closeWrite(); err != nil {
+			return &OpError{Op: "close", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+		return nil
+	}
+	ra, _ := c.fd.raddr.(*SCTPAddr)
+	if ra == nil && c.assocID == 0 {
+		return &OpError{Op: "close", Net: c.fd.net, Source: c.fd.laddr, Addr: nil, Err: errMissingAddress}
+	}
+	var to *SCTPAddr
+	if c.assocID == 0 {
+		to = ra
+	}
+	if err := sendSCTPControl(c.fd, to, &SCTPSndInfo{Flags: SCTPEOF, AssocID: c.assocID}); err != nil {
+		return &OpError{Op: "close", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// PeelOff branches the association identified by assocID off c into a
+// new one-to-one socket, which is returned as a connected [SCTPConn].
+// Data already queued on c for the association moves to the new
+// socket. If assocID is 0, c must have exactly one association, or be
+// a connection returned by [DialSCTP] or a similar function.
+//
+// A dialed connection sets up its association on the first write. If
+// it has none yet, PeelOff starts setting it up with the remote
+// addresses of c and peels it off without waiting for it to complete:
+// writes on the returned connection are queued until then, and a
+// failure to set it up is reported by the next Read or Write.
+//
+// The returned connection supports Read and Write like a TCP
+// connection, with every write sent on stream 0, and inherits the
+// event subscriptions and options of c.
+func (c *SCTPConn) PeelOff(assocID int32) (*SCTPConn, error) {
+	if !c.ok() {
+		return nil, syscall.EINVAL
+	}
+	if assocID == 0 {
+		ids, err := assocIDsSCTP(c.fd)
+		if err == nil && len(ids) == 0 && c.dialed() {
+			ids, err = c.connectDialed()
+		}
+		if err == nil && len(ids) != 1 {
+			err = errMissingAddress
+		}
+		if err != nil {
+			return nil, &OpError{Op: "peeloff", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+		assocID = ids[0]
+	}
+	fd, err := peelOffSCTP(c.fd, assocID)
+	if err != nil {
+		return nil, &OpError{Op: "peeloff", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	pc := newSCTPConn(fd)
+	pc.assocID = assocID
+	pc.peeled = true
+	return pc, nil
+}
+
+// connectDialed starts setting up the association of a dialed c with
+// its remote addresses and returns the identifiers of the associations
+// of c.
+func (c *SCTPConn) connectDialed() ([]int32, error) {
+	// Path tracking edits multiPeer in place while reading.
+	c.mu.Lock()
+	peers := copySCTPAddrs(c.multiPeer)
+	c.mu.Unlock()
+	if len(peers) == 0 {
+		peers = []SCTPAddr{*c.fd.raddr.(*SCTPAddr)}
+	}
+	assocID, err := connectAddrsSCTP(c.fd, peers)
+	if err != nil {
+		return nil, err
+	}
+	if assocID != 0 {
+		c.mu.Lock()
+		if c.dialAssoc == 0 {
+			c.dialAssoc = assocID
+		}
+		c.mu.Unlock()
+		return []int32{assocID}, nil
+	}
+	return assocIDsSCTP(c.fd)
+}
+
+// CloseAssoc gracefully shuts down the association identified by
+// assocID, leaving the socket and its other associations open.
+func (c *SCTPConn) CloseAssoc(assocID int32) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := sendSCTPControl(c.fd, nil, &SCTPSndInfo{Flags: SCTPEOF, AssocID: assocID}); err != nil {
+		return &OpError{Op: "close", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// AbortAssoc aborts the association identified by assocID, discarding
+// any unsent or unacknowledged data, and leaves the socket and its
+// other associations open.
+func (c *SCTPConn) AbortAssoc(assocID int32) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := sendSCTPControl(c.fd, nil, &SCTPSndInfo{Flags: SCTPAbort, AssocID: assocID}); err != nil {
+		return &OpError{Op: "close", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// Drain blocks until every association of c has delivered and had
+// acknowledged all the data queued for sending, or until ctx is done.
+// Calling Drain before Close ensures that final messages are not lost
+// when Close aborts or races the association shutdown.
+//
+// Drain waits for the condition reported by SCTP_SENDER_DRY_EVENT. It
+// polls the association status instead of consuming the notification,
+// so it does not disturb concurrent readers.
+func (c *SCTPConn) Drain(ctx context.Context) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	delay := time.Millisecond
+	for {
+		dry, err := senderDrySCTP(c.fd)
+		if err != nil {
+			return &OpError{Op: "drain", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+		}
+		if dry {
+			return nil
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return &OpError{Op: "drain", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: mapErr(ctx.Err())}
+		case <-t.C:
+		}
+		delay = min(2*delay, 50*time.Millisecond)
+	}
+}
+
+// SetAdaptationLayer sets the adaptation layer indication sent to peers
+// during association setup, via SCTP_ADAPTATION_LAYER.
+// See [SCTPConn.PeerAdaptationLayer] for the indication sent by a peer.
+func (c *SCTPConn) SetAdaptationLayer(ind uint32) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setAdaptationLayerSCTP(c.fd, ind); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// AdaptationLayer returns the adaptation layer indication sent to peers.
+func (c *SCTPConn) AdaptationLayer() (uint32, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	ind, err := adaptationLayerSCTP(c.fd)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return ind, nil
+}
+
+// SetFeatures configures the protocol extensions offered to peers of
+// associations set up after the call. Every extension other than
+// Interleaving is set, so those left false in f are turned off;
+// Interleaving is only turned on when requested, and otherwise keeps
+// the system default (net.sctp.intl_enable on Linux).
+//
+// Associations already set up keep their extensions. This includes
+// the association that [DialSCTPMulti] with several remote addresses
+// or a [Dialer] using [Dialer.SetMultihomeSCTP] sets up during the
+// dial, and any association a listener accepts before the call. Use
+// [Dialer.SetFeaturesSCTP] or [ListenConfig.SetFeaturesSCTP] to apply
+// f when the socket is created.
+func (c *SCTPConn) SetFeatures(f SCTPFeatures) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setFeaturesSCTP(c.fd, f); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// Features returns the protocol extensions offered to peers of new
+// associations.
+func (c *SCTPConn) Features() (SCTPFeatures, error) {
+	if !c.ok() {
+		return SCTPFeatures{}, syscall.EINVAL
+	}
+	f, err := featuresSCTP(c.fd, 0)
+	if err != nil {
+		return SCTPFeatures{}, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return f, nil
+}
+
+// PeerFeatures returns the protocol extensions in effect on the
+// association identified by assocID, that is, the extensions both
+// endpoints agreed on during association setup. An assocID of 0
+// selects the only association of a dialed or peeled-off connection.
+func (c *SCTPConn) PeerFeatures(assocID int32) (SCTPFeatures, error) {
+	if !c.ok() {
+		return SCTPFeatures{}, syscall.EINVAL
+	}
+	if assocID == 0 && !c.peeled {
+		if ids, err := assocIDsSCTP(c.fd); err == nil && len(ids) == 1 && c.fd.raddr != nil {
+			assocID = ids[0]
+		} else {
+			return SCTPFeatures{}, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: syscall.EINVAL}
+		}
+	}
+	f, err := featuresSCTP(c.fd, assocID)
+	if err != nil {
+		return SCTPFeatures{}, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return f, nil
+}
+
+// AssocStats returns the kernel statistics for the association
+// identified by assocID, as reported in [SCTPRcvInfo.AssocID].
+func (c *SCTPConn) AssocStats(assocID int32) (SCTPAssocStats, error) {
+	if !c.ok() {
+		return SCTPAssocStats{}, syscall.EINVAL
+	}
+	st, err := assocStatsSCTP(c.fd, assocID)
+	if err != nil {
+		return SCTPAssocStats{}, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return st, nil
+}
+
+// SetRemoteUDPEncapsPort sets the remote UDP port used to encapsulate
+// SCTP packets in UDP (RFC 6951) via SCTP_REMOTE_UDP_ENCAPS_PORT.
+//
+// An assocID of 0 sets the endpoint default applied to future
+// associations. If addr is non-nil, only the path to that peer address
+// of the association is configured. A port of 0 disables encapsulation.
+func (c *SCTPConn) SetRemoteUDPEncapsPort(assocID int32, addr *SCTPAddr, port int) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if port < 0 || port > 0xffff {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: syscall.EINVAL}
+	}
+	if err := setRemoteUDPEncapsPortSCTP(c.fd, assocID, addr, port); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: err}
+	}
+	return nil
+}
+
+// RemoteUDPEncapsPort returns the remote UDP encapsulation port for the
+// endpoint, association or path selected as in [SCTPConn.SetRemoteUDPEncapsPort].
+func (c *SCTPConn) RemoteUDPEncapsPort(assocID int32, addr *SCTPAddr) (int, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	port, err := remoteUDPEncapsPortSCTP(c.fd, assocID, addr)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: err}
+	}
+	return port, nil
+}
+
+// SetMaxSeg sets the maximum size of the DATA chunks the association
+// identified by assocID fragments messages into, via SCTP_MAXSEG.
+// An assocID of 0 sets the endpoint default. A size of 0 lets the
+// kernel derive the value from the path MTU.
+func (c *SCTPConn) SetMaxSeg(assocID int32, size int) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setMaxSegSCTP(c.fd, assocID, size); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// MaxSeg returns the SCTP_MAXSEG value of the association identified by
+// assocID, or the endpoint default if assocID is 0.
+func (c *SCTPConn) MaxSeg(assocID int32) (int, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	size, err := maxSegSCTP(c.fd, assocID)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return size, nil
+}
+
+// SetAssocContext sets, via SCTP_CONTEXT, the context value reported
+// in the [SCTPRcvInfo] of messages received on the association
+// identified by assocID. An assocID of 0 sets the default for new
+// associations. A relay can use it to tag each association with its
+// route, as the per-message Context of [SCTPSndInfo] tags the
+// messages returned by an [SCTPSendFailedEvent].
+func (c *SCTPConn) SetAssocContext(assocID int32, context uint32) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setAssocContextSCTP(c.fd, assocID, context); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// AssocContext returns the SCTP_CONTEXT value of the association
+// identified by assocID, or the endpoint default if assocID is 0.
+func (c *SCTPConn) AssocContext(assocID int32) (uint32, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	v, err := assocContextSCTP(c.fd, assocID)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return v, nil
+}
+
+// SetDisableFragments controls SCTP_DISABLE_FRAGMENTS. When set,
+// messages larger than the fragmentation point are rejected with an
+// [SCTPMessageSizeError] instead of being split into several chunks.
+func (c *SCTPConn) SetDisableFragments(disable bool) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setDisableFragmentsSCTP(c.fd, disable); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return nil
+}
+
+// DisableFragments reports whether SCTP_DISABLE_FRAGMENTS is set.
+func (c *SCTPConn) DisableFragments() (bool, error) {
+	if !c.ok() {
+		return false, syscall.EINVAL
+	}
+	disable, err := disableFragmentsSCTP(c.fd)
+	if err != nil {
+		return false, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return disable, nil
+}
+
+// SetPLPMTUDProbeInterval sets the packetization-layer path MTU
+// discovery (RFC 8899) probe interval via SCTP_PLPMTUD_PROBE_INTERVAL.
+// An interval of 0 disables PLPMTUD. The assocID and addr select the
+// endpoint default, an association or a single path as in
+// [SCTPConn.SetRemoteUDPEncapsPort].
+func (c *SCTPConn) SetPLPMTUDProbeInterval(assocID int32, addr *SCTPAddr, d time.Duration) error {
+	if !c.ok() {
+		return syscall.EINVAL
+	}
+	if err := setPLPMTUDProbeIntervalSCTP(c.fd, assocID, addr, d); err != nil {
+		return &OpError{Op: "set", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: err}
+	}
+	return nil
+}
+
+// PLPMTUDProbeInterval returns the PLPMTUD probe interval for the
+// endpoint, association or path selected by assocID and addr.
+func (c *SCTPConn) PLPMTUDProbeInterval(assocID int32, addr *SCTPAddr) (time.Duration, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	d, err := plpmtudProbeIntervalSCTP(c.fd, assocID, addr)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: addr.opAddr(), Err: err}
+	}
+	return d, nil
+}
+
+// MaxMessageSize returns the largest message that can currently be sent
+// on the association identified by assocID in a single call.
+//
+// The limit is the socket send buffer size, further capped by the
+// association's fragmentation point when fragmentation is disabled.
+func (c *SCTPConn) MaxMessageSize(assocID int32) (int, error) {
+	if !c.ok() {
+		return 0, syscall.EINVAL
+	}
+	n, err := maxMessageSizeSCTP(c.fd, assocID)
+	if err != nil {
+		return 0, &OpError{Op: "get", Net: c.fd.net, Source: c.fd.laddr, Addr: c.fd.raddr, Err: err}
+	}
+	return n, nil
+}
+
+// SCTPUDPEncapsPort returns the local UDP port on which the kernel
+// accepts SCTP-over-UDP packets, as configured by the net.sctp.udp_port
+// sysctl. A zero port means that UDP encapsulation is disabled.
+func SCTPUDPEncapsPort() (int, error) {
+	return sctpUDPEncapsPort()
+}
+
 // DialSCTP acts like [Dial] for SCTP networks.
 func DialSCTP(network string, laddr, raddr *SCTPAddr) (*SCTPConn, error) {
 	return dialSCTP(context.Background(), nil, network, laddr, raddr)
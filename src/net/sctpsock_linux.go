@@ -10,20 +10,64 @@ import (
 	"errors"
 	"runtime"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
+// errSCTPMessageSize is the error that SCTPMessageSizeError unwraps to.
+var errSCTPMessageSize error = syscall.EMSGSIZE
+
+// sctpMsgEOR is the value of SCTPMsgEOR: recvmsg reports the end of a
+// message with MSG_EOR.
+const sctpMsgEOR = syscall.MSG_EOR
+
+// connectedSCTP reports whether fd is a connected socket, such as a
+// peeled-off association.
+func connectedSCTP(fd *netFD) bool { return fd.isConnected }
+
+// sctpErrno returns the system error number wrapped by err, or 0.
+func sctpErrno(err error) uintptr {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return uintptr(errno)
+	}
+	return 0
+}
+
 // Linux SCTP constants that are not provided by the frozen syscall package.
 const (
-	sctpSockoptInitMsg     = 2
-	sctpSockoptNoDelay     = 3
-	sctpSockoptEvent       = 127
-	sctpSockoptRecvRcvInfo = 32
-	sctpSockoptBindxAdd    = 100
-	sctpSockoptConnectxOld = 107
-	sctpSockoptConnectx    = 110
-	sctpGetPeerAddrs       = 108
-	sctpGetLocalAddrs      = 109
+	sctpSockoptInitMsg       = 2
+	sctpSockoptNoDelay       = 3
+	sctpAutoClose            = 4
+	sctpAdaptationLayer      = 7
+	sctpDisableFragments     = 8
+	sctpIWantMappedV4Addr    = 12
+	sctpMaxSeg               = 13
+	sctpStatus               = 14
+	sctpContext              = 17
+	sctpFragmentInterleave   = 18
+	sctpGetAssocNumber       = 28
+	sctpGetAssocIDList       = 29
+	sctpSockoptEvent         = 127
+	sctpSockoptRecvRcvInfo   = 32
+	sctpSockoptBindxAdd      = 100
+	sctpSockoptPeeloff       = 102
+	sctpSockoptConnectxOld   = 107
+	sctpSockoptConnectx      = 110
+	sctpSockoptConnectx3     = 111
+	sctpGetPeerAddrs         = 108
+	sctpGetLocalAddrs        = 109
+	sctpGetAssocStats        = 112
+	sctpPRSupported          = 113
+	sctpReconfigSupported    = 117
+	sctpEnableStreamReset    = 118
+	sctpResetStreams         = 119
+	sctpInterleaveSupported  = 125
+	sctpASCONFSupported      = 128
+	sctpAuthSupported        = 129
+	sctpECNSupported         = 130
+	sctpRemoteUDPEncapsPort  = 132
+	sctpPLPMTUDProbeInterval = 133
 
 	sctpCmsgTypeSndInfo = 2
 	sctpCmsgTypeRcvInfo = 3
@@ -39,6 +83,13 @@ const (
 	sctpEventAuthentication  = 0x8008
 	sctpEventSenderDry       = 0x8009
 	sctpEventStreamReset     = 0x800a
+	sctpEventSendFailedEvent = 0x800d
+
+	sctpDataSent = 1 // ssf_flags of a message that was at least partly sent
+
+	sctpAllAssoc             = 2 // SCTP_ALL_ASSOC
+	sctpEnableResetStreamReq = 0x01
+	sctpStreamResetOutgoing  = 0x02 // SCTP_STREAM_RESET_OUTGOING in sctp_reset_streams
 )
 
 type sctpInitMsg struct {
@@ -75,17 +126,193 @@ type sctpEvent struct {
 	_       uint8
 }
 
+// sctpNotificationHeader mirrors struct sctp_tlv, the header shared by
+// all notifications.
+type sctpNotificationHeader struct {
+	Type   uint16
+	Flags  uint16
+	Length uint32
+}
+
+type sctpAssocChange struct {
+	sctpNotificationHeader
+	State           uint16
+	Error           uint16
+	OutboundStreams uint16
+	InboundStreams  uint16
+	AssocID         int32
+}
+
+// sctpPaddrChange mirrors struct sctp_paddr_change.
+type sctpPaddrChange struct {
+	sctpNotificationHeader
+	Address [sizeofSockaddrStorage]byte
+	State   int32
+	Error   int32
+	AssocID int32
+}
+
+type sctpAdaptationEvent struct {
+	sctpNotificationHeader
+	Indication uint32
+	AssocID    int32
+}
+
+type sctpSenderDryEvent struct {
+	sctpNotificationHeader
+	AssocID int32
+}
+
+// sctpSendFailedEvent mirrors struct sctp_send_failed_event without its
+// trailing payload.
+type sctpSendFailedEvent struct {
+	sctpNotificationHeader
+	Error   uint32
+	Info    sctpSndInfoLinux
+	AssocID int32
+}
+
+// sctpSndRcvInfo mirrors the deprecated struct sctp_sndrcvinfo.
+type sctpSndRcvInfo struct {
+	Stream     uint16
+	SSN        uint16
+	Flags      uint16
+	_          uint16
+	PPID       uint32
+	Context    uint32
+	TimeToLive uint32
+	TSN        uint32
+	CumTSN     uint32
+	AssocID    int32
+}
+
+// sctpSendFailed mirrors the deprecated struct sctp_send_failed without
+// its trailing payload. It is delivered instead of
+// sctpSendFailedEvent by kernels without SCTP_SEND_FAILED_EVENT.
+type sctpSendFailed struct {
+	sctpNotificationHeader
+	Error   uint32
+	Info    sctpSndRcvInfo
+	AssocID int32
+}
+
+// sctpStreamResetEvent mirrors struct sctp_stream_reset_event without
+// its trailing stream list.
+type sctpStreamResetEvent struct {
+	sctpNotificationHeader
+	AssocID int32
+}
+
 type sctpGetAddrs struct {
 	AssocID int32
 	AddrNum uint32
 }
 
+// The kernel aligns struct sockaddr_storage to the size of a pointer,
+// which leaves a hole after a leading sctp_assoc_t on 64-bit systems.
+// The holes are spelled out, and a leading [0]uintptr gives a
+// structure the alignment of its sockaddr_storage member, so that the
+// structures below have the kernel layout on every architecture.
 const (
-	sizeofSCTPInitMsg      = int(unsafe.Sizeof(sctpInitMsg{}))
-	sizeofSCTPSndInfoLinux = int(unsafe.Sizeof(sctpSndInfoLinux{}))
-	sizeofSCTPRcvInfoLinux = int(unsafe.Sizeof(sctpRcvInfoLinux{}))
-	sizeofSCTPEvent        = int(unsafe.Sizeof(sctpEvent{}))
-	sizeofSCTPGetAddrs     = int(unsafe.Sizeof(sctpGetAddrs{}))
+	sctpSSHole   = unsafe.Sizeof(uintptr(0)) - 4
+	sctpStatHole = (sctpU64Align - (4+sctpSSHole+sizeofSockaddrStorage)%sctpU64Align) % sctpU64Align
+)
+
+// sctpAssocStats mirrors struct sctp_assoc_stats.
+type sctpAssocStats struct {
+	AssocID      int32
+	_            [sctpSSHole]byte
+	ObsRTOAddr   [sizeofSockaddrStorage]byte
+	_            [sctpStatHole]byte
+	MaxRTO       uint64
+	ISACKs       uint64
+	OSACKs       uint64
+	OPackets     uint64
+	IPackets     uint64
+	RtxChunks    uint64
+	OutOfSeqTSNs uint64
+	IDupChunks   uint64
+	GapCnt       uint64
+	OUODChunks   uint64
+	IUODChunks   uint64
+	OODChunks    uint64
+	IODChunks    uint64
+	OCtrlChunks  uint64
+	ICtrlChunks  uint64
+}
+
+// sctpUDPEncaps mirrors struct sctp_udpencaps.
+type sctpUDPEncaps struct {
+	_       [0]uintptr
+	AssocID int32
+	_       [sctpSSHole]byte
+	Address [sizeofSockaddrStorage]byte
+	Port    uint16 // network byte order
+}
+
+type sctpAssocValue struct {
+	AssocID int32
+	Value   uint32
+}
+
+type sctpPeeloffArg struct {
+	AssocID int32
+	SD      int32
+}
+
+// sctpGetAddrsOld is struct sctp_getaddrs_old, the argument of
+// SCTP_SOCKOPT_CONNECTX3.
+type sctpGetAddrsOld struct {
+	AssocID int32
+	AddrNum int32 // size of the address list in bytes
+	Addrs   *byte
+}
+
+// sctpProbeInterval mirrors struct sctp_probeinterval.
+type sctpProbeInterval struct {
+	_        [0]uintptr
+	AssocID  int32
+	_        [sctpSSHole]byte
+	Address  [sizeofSockaddrStorage]byte
+	Interval uint32 // milliseconds
+}
+
+// sctpStatusLinux mirrors struct sctp_status. The trailing
+// struct sctp_paddrinfo is packed and kept as raw bytes.
+type sctpStatusLinux struct {
+	AssocID            int32
+	State              int32
+	Rwnd               uint32
+	UnackData          uint16
+	PendData           uint16
+	InStrms            uint16
+	OutStrms           uint16
+	FragmentationPoint uint32
+	Primary            [152]byte
+}
+
+const (
+	sizeofSCTPInitMsg            = int(unsafe.Sizeof(sctpInitMsg{}))
+	sizeofSCTPSndInfoLinux       = int(unsafe.Sizeof(sctpSndInfoLinux{}))
+	sizeofSCTPRcvInfoLinux       = int(unsafe.Sizeof(sctpRcvInfoLinux{}))
+	sizeofSCTPEvent              = int(unsafe.Sizeof(sctpEvent{}))
+	sizeofSCTPGetAddrs           = int(unsafe.Sizeof(sctpGetAddrs{}))
+	sizeofSCTPNotificationHeader = int(unsafe.Sizeof(sctpNotificationHeader{}))
+	sizeofSCTPAssocChange        = int(unsafe.Sizeof(sctpAssocChange{}))
+	sizeofSCTPAdaptationEvent    = int(unsafe.Sizeof(sctpAdaptationEvent{}))
+	sizeofSCTPSenderDryEvent     = int(unsafe.Sizeof(sctpSenderDryEvent{}))
+	sizeofSCTPStreamResetEvent   = int(unsafe.Sizeof(sctpStreamResetEvent{}))
+	sizeofSCTPSendFailedEvent    = int(unsafe.Sizeof(sctpSendFailedEvent{}))
+	sizeofSCTPSendFailed         = int(unsafe.Sizeof(sctpSendFailed{}))
+	sizeofSCTPPaddrChange        = int(unsafe.Sizeof(sctpPaddrChange{}))
+	sizeofSCTPAssocStats         = int(unsafe.Sizeof(sctpAssocStats{}))
+	sizeofSCTPUDPEncaps          = int(unsafe.Sizeof(sctpUDPEncaps{}))
+	sizeofSCTPAssocValue         = int(unsafe.Sizeof(sctpAssocValue{}))
+	sizeofSCTPPeeloffArg         = int(unsafe.Sizeof(sctpPeeloffArg{}))
+	sizeofSCTPProbeInterval      = int(unsafe.Sizeof(sctpProbeInterval{}))
+	sizeofSCTPStatusLinux        = int(unsafe.Sizeof(sctpStatusLinux{}))
+
+	sizeofSockaddrStorage = 128
 )
 
 func sctpOOBBufferSize() int {
@@ -146,6 +373,120 @@ func parseSCTPRcvInfo(oob []byte) (*SCTPRcvInfo, error) {
 	return nil, nil
 }
 
+func parseSCTPNotification(b []byte) (SCTPNotification, error) {
+	if len(b) < sizeofSCTPNotificationHeader {
+		return nil, errors.New("short SCTP notification")
+	}
+	var h sctpNotificationHeader
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&h)), sizeofSCTPNotificationHeader), b)
+	if int(h.Length) < sizeofSCTPNotificationHeader || int(h.Length) > len(b) {
+		return nil, errors.New("truncated SCTP notification")
+	}
+	b = b[:h.Length]
+	switch h.Type {
+	case sctpEventAssociation:
+		if len(b) < sizeofSCTPAssocChange {
+			return nil, errors.New("short SCTP_ASSOC_CHANGE notification")
+		}
+		var ac sctpAssocChange
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&ac)), sizeofSCTPAssocChange), b)
+		n := &SCTPAssocChangeEvent{
+			State:           SCTPAssocChangeState(ac.State),
+			Error:           ac.Error,
+			OutboundStreams: ac.OutboundStreams,
+			InboundStreams:  ac.InboundStreams,
+			AssocID:         ac.AssocID,
+		}
+		if len(b) > sizeofSCTPAssocChange {
+			n.Info = append([]byte(nil), b[sizeofSCTPAssocChange:]...)
+		}
+		return n, nil
+	case sctpEventAddress:
+		if len(b) < sizeofSCTPPaddrChange {
+			return nil, errors.New("short SCTP_PEER_ADDR_CHANGE notification")
+		}
+		var pc sctpPaddrChange
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&pc)), sizeofSCTPPaddrChange), b)
+		addrs, err := parseRawSockaddrsSCTP(pc.Address[:], 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(addrs) != 1 {
+			return nil, errors.New("missing address in SCTP_PEER_ADDR_CHANGE notification")
+		}
+		return &SCTPPeerAddrChangeEvent{
+			Addr:    addrs[0],
+			State:   SCTPPeerAddrState(pc.State),
+			Error:   pc.Error,
+			AssocID: pc.AssocID,
+		}, nil
+	case sctpEventAdaptation:
+		if len(b) < sizeofSCTPAdaptationEvent {
+			return nil, errors.New("short SCTP_ADAPTATION_INDICATION notification")
+		}
+		var ae sctpAdaptationEvent
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&ae)), sizeofSCTPAdaptationEvent), b)
+		return &SCTPAdaptationEvent{Indication: ae.Indication, AssocID: ae.AssocID}, nil
+	case sctpEventSenderDry:
+		if len(b) < sizeofSCTPSenderDryEvent {
+			return nil, errors.New("short SCTP_SENDER_DRY_EVENT notification")
+		}
+		var de sctpSenderDryEvent
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&de)), sizeofSCTPSenderDryEvent), b)
+		return &SCTPSenderDryEvent{AssocID: de.AssocID}, nil
+	case sctpEventSendFailedEvent:
+		if len(b) < sizeofSCTPSendFailedEvent {
+			return nil, errors.New("short SCTP_SEND_FAILED_EVENT notification")
+		}
+		var sf sctpSendFailedEvent
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&sf)), sizeofSCTPSendFailedEvent), b)
+		return &SCTPSendFailedEvent{
+			Info: SCTPSndInfo{
+				Stream:  sf.Info.Stream,
+				Flags:   sf.Info.Flags,
+				PPID:    sf.Info.PPID,
+				Context: sf.Info.Context,
+				AssocID: sf.AssocID,
+			},
+			Payload: append([]byte(nil), b[sizeofSCTPSendFailedEvent:]...),
+			Error:   sf.Error,
+			Unsent:  h.Flags&sctpDataSent == 0,
+		}, nil
+	case sctpEventSendFailure:
+		if len(b) < sizeofSCTPSendFailed {
+			return nil, errors.New("short SCTP_SEND_FAILED notification")
+		}
+		var sf sctpSendFailed
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&sf)), sizeofSCTPSendFailed), b)
+		return &SCTPSendFailedEvent{
+			Info: SCTPSndInfo{
+				Stream:  sf.Info.Stream,
+				Flags:   sf.Info.Flags,
+				PPID:    sf.Info.PPID,
+				Context: sf.Info.Context,
+				AssocID: sf.AssocID,
+			},
+			Payload: append([]byte(nil), b[sizeofSCTPSendFailed:]...),
+			Error:   sf.Error,
+			Unsent:  h.Flags&sctpDataSent == 0,
+		}, nil
+	case sctpEventStreamReset:
+		if len(b) < sizeofSCTPStreamResetEvent {
+			return nil, errors.New("short SCTP_STREAM_RESET_EVENT notification")
+		}
+		var re sctpStreamResetEvent
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&re)), sizeofSCTPStreamResetEvent), b)
+		n := &SCTPStreamResetEvent{Flags: h.Flags, AssocID: re.AssocID}
+		for p := b[sizeofSCTPStreamResetEvent:]; len(p) >= 2; p = p[2:] {
+			var id uint16
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(&id)), 2), p)
+			n.Streams = append(n.Streams, id)
+		}
+		return n, nil
+	}
+	return &SCTPUnknownEvent{Type: h.Type, Flags: h.Flags, Data: append([]byte(nil), b...)}, nil
+}
+
 func setNoDelaySCTP(fd *netFD, noDelay bool) error {
 	err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpSockoptNoDelay, boolint(noDelay))
 	runtime.KeepAlive(fd)
@@ -170,10 +511,41 @@ func setSCTPInitOptions(fd *netFD, opts SCTPInitOptions) error {
 	return nil
 }
 
+func setRecvRcvInfoSCTP(fd *netFD, on bool) error {
+	err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpSockoptRecvRcvInfo, boolint(on))
+	runtime.KeepAlive(fd)
+	return wrapSyscallError("setsockopt", err)
+}
+
+// setMappedV4SCTP sets SCTP_I_WANT_MAPPED_V4_ADDR, which makes an
+// AF_INET6 socket report IPv4 peers as IPv4-mapped IPv6 addresses
+// rather than as sockaddr_in.
+func setMappedV4SCTP(fd *netFD, on bool) error {
+	err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpIWantMappedV4Addr, boolint(on))
+	runtime.KeepAlive(fd)
+	return wrapSyscallError("setsockopt", err)
+}
+
+func setAutoCloseSCTP(fd *netFD, sec int) error {
+	err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpAutoClose, sec)
+	runtime.KeepAlive(fd)
+	return wrapSyscallError("setsockopt", err)
+}
+
+func autoCloseSCTP(fd *netFD) (int, error) {
+	sec, err := fd.pfd.GetsockoptInt(syscall.IPPROTO_SCTP, sctpAutoClose)
+	runtime.KeepAlive(fd)
+	if err != nil {
+		return 0, wrapSyscallError("getsockopt", err)
+	}
+	return sec, nil
+}
+
 func subscribeSCTPEvents(fd *netFD, mask SCTPEventMask) error {
 	events := []struct {
-		typeID uint16
-		on     bool
+		typeID   uint16
+		on       bool
+		optional bool // unknown to older kernels
 	}{
 		{typeID: sctpEventDataIO, on: mask.DataIO},
 		{typeID: sctpEventAssociation, on: mask.Association},
@@ -186,10 +558,15 @@ func subscribeSCTPEvents(fd *netFD, mask SCTPEventMask) error {
 		{typeID: sctpEventAuthentication, on: mask.Authentication},
 		{typeID: sctpEventSenderDry, on: mask.SenderDry},
 		{typeID: sctpEventStreamReset, on: mask.StreamReset},
+		// Preferred over SCTP_SEND_FAILED where supported.
+		{typeID: sctpEventSendFailedEvent, on: mask.SendFailure, optional: true},
 	}
 	for _, evt := range events {
 		e := sctpEvent{Type: evt.typeID, On: uint8(boolint(evt.on))}
 		if err := setSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpSockoptEvent, unsafe.Slice((*byte)(unsafe.Pointer(&e)), sizeofSCTPEvent)); err != nil {
+			if evt.optional && errors.Is(err, syscall.EINVAL) {
+				continue
+			}
 			return err
 		}
 	}
@@ -201,15 +578,7 @@ func setSockoptBytes(fd *netFD, level, name int, value []byte) error {
 	if len(value) > 0 {
 		ptr = unsafe.Pointer(&value[0])
 	}
-	_, _, errno := syscall.Syscall6(
-		syscall.SYS_SETSOCKOPT,
-		uintptr(fd.pfd.Sysfd),
-		uintptr(level),
-		uintptr(name),
-		uintptr(ptr),
-		uintptr(len(value)),
-		0,
-	)
+	_, errno := setsockoptSCTP(fd.pfd.Sysfd, level, name, ptr, uintptr(len(value)))
 	runtime.KeepAlive(fd)
 	if errno != 0 {
 		return wrapSyscallError("setsockopt", errno)
@@ -217,6 +586,417 @@ func setSockoptBytes(fd *netFD, level, name int, value []byte) error {
 	return nil
 }
 
+func getSockoptBytes(fd *netFD, level, name int, value []byte) (int, error) {
+	var ptr unsafe.Pointer
+	if len(value) > 0 {
+		ptr = unsafe.Pointer(&value[0])
+	}
+	optLen := uint32(len(value))
+	errno := getsockoptSCTP(fd.pfd.Sysfd, level, name, ptr, &optLen)
+	runtime.KeepAlive(fd)
+	if errno != 0 {
+		return 0, wrapSyscallError("getsockopt", errno)
+	}
+	return int(optLen), nil
+}
+
+func assocStatsSCTP(fd *netFD, assocID int32) (SCTPAssocStats, error) {
+	st := sctpAssocStats{AssocID: assocID}
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpGetAssocStats, unsafe.Slice((*byte)(unsafe.Pointer(&st)), sizeofSCTPAssocStats)); err != nil {
+		return SCTPAssocStats{}, err
+	}
+	out := SCTPAssocStats{
+		MaxRTO:             time.Duration(st.MaxRTO) * time.Millisecond,
+		InSACKs:            st.ISACKs,
+		OutSACKs:           st.OSACKs,
+		InPackets:          st.IPackets,
+		OutPackets:         st.OPackets,
+		RetransChunks:      st.RtxChunks,
+		OutOfSeqTSNs:       st.OutOfSeqTSNs,
+		DupChunks:          st.IDupChunks,
+		GapAcks:            st.GapCnt,
+		InUnorderedChunks:  st.IUODChunks,
+		OutUnorderedChunks: st.OUODChunks,
+		InOrderedChunks:    st.IODChunks,
+		OutOrderedChunks:   st.OODChunks,
+		InControlChunks:    st.ICtrlChunks,
+		OutControlChunks:   st.OCtrlChunks,
+	}
+	// A zero family means no RTO has been observed since the last query.
+	if *(*uint16)(unsafe.Pointer(&st.ObsRTOAddr[0])) != 0 {
+		addrs, err := parseRawSockaddrsSCTP(st.ObsRTOAddr[:], 1)
+		if err != nil {
+			return SCTPAssocStats{}, err
+		}
+		if len(addrs) == 1 {
+			out.MaxRTOAddr = &addrs[0]
+		}
+	}
+	return out, nil
+}
+
+// sockaddrStorageSCTP encodes addr as a struct sockaddr_storage for
+// socket options that select a single peer transport. A nil addr
+// encodes the zero (AF_UNSPEC) address.
+func sockaddrStorageSCTP(family int, addr *SCTPAddr) ([sizeofSockaddrStorage]byte, error) {
+	var ss [sizeofSockaddrStorage]byte
+	if addr == nil {
+		return ss, nil
+	}
+	b, err := marshalRawSockaddrsSCTP(family, []SCTPAddr{*addr})
+	if err != nil {
+		return ss, err
+	}
+	copy(ss[:], b)
+	return ss, nil
+}
+
+func setRemoteUDPEncapsPortSCTP(fd *netFD, assocID int32, addr *SCTPAddr, port int) error {
+	ss, err := sockaddrStorageSCTP(fd.family, addr)
+	if err != nil {
+		return err
+	}
+	ue := sctpUDPEncaps{AssocID: assocID, Address: ss, Port: htons(uint16(port))}
+	return setSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpRemoteUDPEncapsPort, unsafe.Slice((*byte)(unsafe.Pointer(&ue)), sizeofSCTPUDPEncaps))
+}
+
+func remoteUDPEncapsPortSCTP(fd *netFD, assocID int32, addr *SCTPAddr) (int, error) {
+	ss, err := sockaddrStorageSCTP(fd.family, addr)
+	if err != nil {
+		return 0, err
+	}
+	ue := sctpUDPEncaps{AssocID: assocID, Address: ss}
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpRemoteUDPEncapsPort, unsafe.Slice((*byte)(unsafe.Pointer(&ue)), sizeofSCTPUDPEncaps)); err != nil {
+		return 0, err
+	}
+	return int(ntohs(ue.Port)), nil
+}
+
+func setAssocValueSCTP(fd *netFD, opt int, assocID int32, v uint32) error {
+	av := sctpAssocValue{AssocID: assocID, Value: v}
+	return setSockoptBytes(fd, syscall.IPPROTO_SCTP, opt, unsafe.Slice((*byte)(unsafe.Pointer(&av)), sizeofSCTPAssocValue))
+}
+
+func assocValueSCTP(fd *netFD, opt int, assocID int32) (uint32, error) {
+	av := sctpAssocValue{AssocID: assocID}
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, opt, unsafe.Slice((*byte)(unsafe.Pointer(&av)), sizeofSCTPAssocValue)); err != nil {
+		return 0, err
+	}
+	return av.Value, nil
+}
+
+// sctpFeatureOpts lists the extension socket options in the order they
+// are applied: ASCONF is only offered when AUTH is enabled.
+// SCTP_INTERLEAVING_SUPPORTED is handled separately by setFeaturesSCTP.
+var sctpFeatureOpts = [...]struct {
+	opt   int
+	field func(*SCTPFeatures) *bool
+}{
+	{sctpAuthSupported, func(f *SCTPFeatures) *bool { return &f.Auth }},
+	{sctpASCONFSupported, func(f *SCTPFeatures) *bool { return &f.ASCONF }},
+	{sctpECNSupported, func(f *SCTPFeatures) *bool { return &f.ECN }},
+	{sctpPRSupported, func(f *SCTPFeatures) *bool { return &f.PartialReliability }},
+	{sctpReconfigSupported, func(f *SCTPFeatures) *bool { return &f.StreamReconfig }},
+}
+
+func setAdaptationLayerSCTP(fd *netFD, ind uint32) error {
+	return setSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpAdaptationLayer, unsafe.Slice((*byte)(unsafe.Pointer(&ind)), 4))
+}
+
+func adaptationLayerSCTP(fd *netFD) (uint32, error) {
+	var ind uint32
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpAdaptationLayer, unsafe.Slice((*byte)(unsafe.Pointer(&ind)), 4)); err != nil {
+		return 0, err
+	}
+	return ind, nil
+}
+
+func setFeaturesSCTP(fd *netFD, f SCTPFeatures) error {
+	for _, o := range sctpFeatureOpts {
+		if err := setAssocValueSCTP(fd, o.opt, 0, uint32(boolint(*o.field(&f)))); err != nil {
+			return err
+		}
+	}
+	if !f.Interleaving {
+		// The kernel refuses SCTP_INTERLEAVING_SUPPORTED with EPERM
+		// unless net.sctp.intl_enable is set and fragments are
+		// interleaved, so the system default is left alone.
+		return nil
+	}
+	// I-DATA requires full fragment interleaving (level 2).
+	if err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpFragmentInterleave, 2); err != nil {
+		runtime.KeepAlive(fd)
+		return wrapSyscallError("setsockopt", err)
+	}
+	runtime.KeepAlive(fd)
+	return setAssocValueSCTP(fd, sctpInterleaveSupported, 0, 1)
+}
+
+func featuresSCTP(fd *netFD, assocID int32) (SCTPFeatures, error) {
+	var f SCTPFeatures
+	for _, o := range sctpFeatureOpts {
+		v, err := assocValueSCTP(fd, o.opt, assocID)
+		if err != nil {
+			return SCTPFeatures{}, err
+		}
+		*o.field(&f) = v != 0
+	}
+	v, err := assocValueSCTP(fd, sctpInterleaveSupported, assocID)
+	if err != nil {
+		return SCTPFeatures{}, err
+	}
+	f.Interleaving = v != 0
+	return f, nil
+}
+
+func setMaxSegSCTP(fd *netFD, assocID int32, size int) error {
+	if size < 0 {
+		return syscall.EINVAL
+	}
+	return setAssocValueSCTP(fd, sctpMaxSeg, assocID, uint32(size))
+}
+
+func maxSegSCTP(fd *netFD, assocID int32) (int, error) {
+	v, err := assocValueSCTP(fd, sctpMaxSeg, assocID)
+	return int(v), err
+}
+
+func setDisableFragmentsSCTP(fd *netFD, disable bool) error {
+	err := fd.pfd.SetsockoptInt(syscall.IPPROTO_SCTP, sctpDisableFragments, boolint(disable))
+	runtime.KeepAlive(fd)
+	return wrapSyscallError("setsockopt", err)
+}
+
+func disableFragmentsSCTP(fd *netFD) (bool, error) {
+	v, err := fd.pfd.GetsockoptInt(syscall.IPPROTO_SCTP, sctpDisableFragments)
+	runtime.KeepAlive(fd)
+	if err != nil {
+		return false, wrapSyscallError("getsockopt", err)
+	}
+	return v != 0, nil
+}
+
+func setPLPMTUDProbeIntervalSCTP(fd *netFD, assocID int32, addr *SCTPAddr, d time.Duration) error {
+	if d < 0 {
+		return syscall.EINVAL
+	}
+	ss, err := sockaddrStorageSCTP(fd.family, addr)
+	if err != nil {
+		return err
+	}
+	pi := sctpProbeInterval{AssocID: assocID, Address: ss, Interval: uint32(d / time.Millisecond)}
+	return setSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpPLPMTUDProbeInterval, unsafe.Slice((*byte)(unsafe.Pointer(&pi)), sizeofSCTPProbeInterval))
+}
+
+func plpmtudProbeIntervalSCTP(fd *netFD, assocID int32, addr *SCTPAddr) (time.Duration, error) {
+	ss, err := sockaddrStorageSCTP(fd.family, addr)
+	if err != nil {
+		return 0, err
+	}
+	pi := sctpProbeInterval{AssocID: assocID, Address: ss}
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpPLPMTUDProbeInterval, unsafe.Slice((*byte)(unsafe.Pointer(&pi)), sizeofSCTPProbeInterval)); err != nil {
+		return 0, err
+	}
+	return time.Duration(pi.Interval) * time.Millisecond, nil
+}
+
+func statusSCTP(fd *netFD, assocID int32) (sctpStatusLinux, error) {
+	st := sctpStatusLinux{AssocID: assocID}
+	if _, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpStatus, unsafe.Slice((*byte)(unsafe.Pointer(&st)), sizeofSCTPStatusLinux)); err != nil {
+		return sctpStatusLinux{}, err
+	}
+	return st, nil
+}
+
+// peerPathsSCTP returns the peer addresses of the association, marking
+// the primary path reported by SCTP_STATUS. The kernel does not report
+// reachability here, so every path starts out available.
+func peerPathsSCTP(fd *netFD, assocID int32) ([]SCTPPath, error) {
+	addrs, err := peerAddrsSCTP(fd, assocID)
+	if err != nil {
+		return nil, err
+	}
+	var primary *SCTPAddr
+	if st, err := statusSCTP(fd, assocID); err == nil {
+		// struct sctp_paddrinfo starts with spinfo_assoc_id.
+		if p, err := parseRawSockaddrsSCTP(st.Primary[4:], 1); err == nil && len(p) == 1 {
+			primary = &p[0]
+		}
+	}
+	paths := make([]SCTPPath, len(addrs))
+	for i := range addrs {
+		paths[i] = SCTPPath{
+			Addr:    addrs[i],
+			State:   SCTPAddrAvailable,
+			Primary: primary != nil && primary.equal(&addrs[i]),
+		}
+	}
+	return paths, nil
+}
+
+// maxMessageSizeSCTP mirrors the EMSGSIZE checks of the kernel's
+// sctp_sendmsg: messages may not exceed the send buffer and, with
+// fragmentation disabled, the association's fragmentation point.
+func maxMessageSizeSCTP(fd *netFD, assocID int32) (int, error) {
+	sndbuf, err := fd.pfd.GetsockoptInt(syscall.SOL_SOCKET, syscall.SO_SNDBUF)
+	runtime.KeepAlive(fd)
+	if err != nil {
+		return 0, wrapSyscallError("getsockopt", err)
+	}
+	disable, err := disableFragmentsSCTP(fd)
+	if err != nil || !disable {
+		return sndbuf, err
+	}
+	st, err := statusSCTP(fd, assocID)
+	if err != nil {
+		return 0, err
+	}
+	return min(sndbuf, int(st.FragmentationPoint)), nil
+}
+
+// assocIDsSCTP returns the identifiers of the associations of fd.
+func assocIDsSCTP(fd *netFD) ([]int32, error) {
+	for {
+		n, err := fd.pfd.GetsockoptInt(syscall.IPPROTO_SCTP, sctpGetAssocNumber)
+		runtime.KeepAlive(fd)
+		if err != nil {
+			return nil, wrapSyscallError("getsockopt", err)
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		buf := make([]int32, 1+n)
+		_, err = getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpGetAssocIDList, unsafe.Slice((*byte)(unsafe.Pointer(&buf[0])), 4*len(buf)))
+		if errors.Is(err, syscall.EINVAL) {
+			// Associations were added since SCTP_GET_ASSOC_NUMBER.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[1 : 1+min(int(uint32(buf[0])), n)], nil
+	}
+}
+
+// senderDrySCTP reports whether no association of fd has user data
+// pending or awaiting acknowledgement.
+func senderDrySCTP(fd *netFD) (bool, error) {
+	ids, err := assocIDsSCTP(fd)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range ids {
+		st, err := statusSCTP(fd, id)
+		if errors.Is(err, syscall.EINVAL) {
+			// The association went away.
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		if st.UnackData != 0 || st.PendData != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func setAssocContextSCTP(fd *netFD, assocID int32, context uint32) error {
+	return setAssocValueSCTP(fd, sctpContext, assocID, context)
+}
+
+func assocContextSCTP(fd *netFD, assocID int32) (uint32, error) {
+	return assocValueSCTP(fd, sctpContext, assocID)
+}
+
+// enableStreamResetSCTP allows the association identified by assocID,
+// or the endpoint and all its associations when it is 0, to send and
+// accept requests to reset outgoing streams (SCTP_ENABLE_STREAM_RESET).
+func enableStreamResetSCTP(fd *netFD, assocID int32) error {
+	if assocID == 0 {
+		assocID = sctpAllAssoc
+	}
+	return setAssocValueSCTP(fd, sctpEnableStreamReset, assocID, sctpEnableResetStreamReq)
+}
+
+// sctpResetBusy reports whether err, returned by resetStreamsSCTP,
+// means that the reset cannot be requested yet because data is still
+// queued on the streams or another reset is in progress.
+func sctpResetBusy(err error) bool { return errors.Is(err, syscall.EAGAIN) }
+
+// sctpResetUnsupported reports whether err, returned by
+// resetStreamsSCTP, means that the kernel has no stream
+// reconfiguration support.
+func sctpResetUnsupported(err error) bool { return errors.Is(err, syscall.ENOPROTOOPT) }
+
+// resetStreamsSCTP requests an RFC 6525 reset of the given outgoing
+// streams of the association identified by assocID (SCTP_RESET_STREAMS).
+func resetStreamsSCTP(fd *netFD, assocID int32, streams []uint16) error {
+	// struct sctp_reset_streams: assoc_id, flags, number_streams and
+	// the stream list.
+	b := make([]byte, 8+2*len(streams))
+	*(*int32)(unsafe.Pointer(&b[0])) = assocID
+	*(*uint16)(unsafe.Pointer(&b[4])) = sctpStreamResetOutgoing
+	*(*uint16)(unsafe.Pointer(&b[6])) = uint16(len(streams))
+	for i, id := range streams {
+		*(*uint16)(unsafe.Pointer(&b[8+2*i])) = id
+	}
+	return setSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpResetStreams, b)
+}
+
+// sendSCTPControl sends a message without payload carrying only info,
+// as used by SCTP_EOF and SCTP_ABORT. It bypasses syscall.SendmsgN,
+// which would add a dummy payload byte on SOCK_SEQPACKET sockets.
+func sendSCTPControl(fd *netFD, to *SCTPAddr, info *SCTPSndInfo) error {
+	oob, err := marshalSCTPSndInfo(info)
+	if err != nil {
+		return err
+	}
+	var msg syscall.Msghdr
+	if to != nil {
+		name, err := marshalRawSockaddrsSCTP(fd.family, []SCTPAddr{*to})
+		if err != nil {
+			return err
+		}
+		msg.Name = &name[0]
+		msg.Namelen = uint32(len(name))
+	}
+	msg.Control = &oob[0]
+	msg.SetControllen(len(oob))
+	var errno syscall.Errno
+	err = fd.pfd.RawWrite(func(s uintptr) bool {
+		errno = sendmsgSCTP(int(s), &msg, 0)
+		return errno != syscall.EAGAIN
+	})
+	runtime.KeepAlive(fd)
+	if err != nil {
+		return err
+	}
+	if errno != 0 {
+		return wrapSyscallError("sendmsg", errno)
+	}
+	return nil
+}
+
+const sctpUDPPortSysctl = "/proc/sys/net/sctp/udp_port"
+
+func sctpUDPEncapsPort() (int, error) {
+	f, err := open(sctpUDPPortSysctl)
+	if err != nil {
+		return 0, err
+	}
+	defer f.close()
+	line, ok := f.readLine()
+	if !ok {
+		return 0, errors.New("empty " + sctpUDPPortSysctl)
+	}
+	port, _, ok := dtoi(line)
+	if !ok {
+		return 0, errors.New("invalid " + sctpUDPPortSysctl)
+	}
+	return port, nil
+}
+
 func bindAddrsSCTP(fd *netFD, addrs []SCTPAddr) error {
 	if len(addrs) == 0 {
 		return nil
@@ -236,37 +1016,38 @@ func connectAddrsSCTP(fd *netFD, addrs []SCTPAddr) (int32, error) {
 	if err != nil {
 		return 0, err
 	}
-	r0, _, errno := syscall.Syscall6(
-		syscall.SYS_SETSOCKOPT,
-		uintptr(fd.pfd.Sysfd),
-		uintptr(syscall.IPPROTO_SCTP),
-		uintptr(sctpSockoptConnectx),
-		uintptr(unsafe.Pointer(&b[0])),
-		uintptr(len(b)),
-		0,
-	)
+	// SCTP_SOCKOPT_CONNECTX3 reports the association identifier even
+	// when the non-blocking connect returns EINPROGRESS, which the
+	// setsockopt variants do not.
+	arg := sctpGetAddrsOld{AddrNum: int32(len(b)), Addrs: &b[0]}
+	optLen := uint32(unsafe.Sizeof(arg))
+	errno := getsockoptSCTP(fd.pfd.Sysfd, syscall.IPPROTO_SCTP, sctpSockoptConnectx3, unsafe.Pointer(&arg), &optLen)
+	runtime.KeepAlive(fd)
+	runtime.KeepAlive(b)
+	if errno == 0 || errno == syscall.EINPROGRESS {
+		return arg.AssocID, nil
+	}
+	if errno != syscall.ENOPROTOOPT {
+		return 0, wrapSyscallError("getsockopt", errno)
+	}
+	// Fallbacks used by older kernels.
+	r0, errno := setsockoptSCTP(fd.pfd.Sysfd, syscall.IPPROTO_SCTP, sctpSockoptConnectx, unsafe.Pointer(&b[0]), uintptr(len(b)))
 	runtime.KeepAlive(fd)
-	if errno == 0 || errno == syscall.EINPROGRESS || errno == syscall.EALREADY {
+	if errno == 0 {
 		return int32(r0), nil
 	}
+	if errno == syscall.EINPROGRESS || errno == syscall.EALREADY {
+		return 0, nil
+	}
 	if errno != syscall.ENOPROTOOPT {
 		return 0, wrapSyscallError("setsockopt", errno)
 	}
-	// Fallback used by older kernels.
-	r0, _, errno = syscall.Syscall6(
-		syscall.SYS_SETSOCKOPT,
-		uintptr(fd.pfd.Sysfd),
-		uintptr(syscall.IPPROTO_SCTP),
-		uintptr(sctpSockoptConnectxOld),
-		uintptr(unsafe.Pointer(&b[0])),
-		uintptr(len(b)),
-		0,
-	)
+	_, errno = setsockoptSCTP(fd.pfd.Sysfd, syscall.IPPROTO_SCTP, sctpSockoptConnectxOld, unsafe.Pointer(&b[0]), uintptr(len(b)))
 	runtime.KeepAlive(fd)
 	if errno != 0 && errno != syscall.EINPROGRESS && errno != syscall.EALREADY {
 		return 0, wrapSyscallError("setsockopt", errno)
 	}
-	return int32(r0), nil
+	return 0, nil
 }
 
 func localAddrsSCTP(fd *netFD, assocID int32) ([]SCTPAddr, error) {
@@ -284,15 +1065,7 @@ func getAddrsSCTP(fd *netFD, opt int, assocID int32) ([]SCTPAddr, error) {
 	h.AssocID = assocID
 	optLen := uint32(len(buf))
 
-	_, _, errno := syscall.Syscall6(
-		syscall.SYS_GETSOCKOPT,
-		uintptr(fd.pfd.Sysfd),
-		uintptr(syscall.IPPROTO_SCTP),
-		uintptr(opt),
-		uintptr(unsafe.Pointer(&buf[0])),
-		uintptr(unsafe.Pointer(&optLen)),
-		0,
-	)
+	errno := getsockoptSCTP(fd.pfd.Sysfd, syscall.IPPROTO_SCTP, opt, unsafe.Pointer(&buf[0]), &optLen)
 	runtime.KeepAlive(fd)
 	if errno != 0 {
 		return nil, wrapSyscallError("getsockopt", errno)
@@ -327,6 +1100,13 @@ func parseRawSockaddrsSCTP(data []byte, n int) ([]SCTPAddr, error) {
 			copy(unsafe.Slice((*byte)(unsafe.Pointer(&sa)), syscall.SizeofSockaddrInet6), data[:syscall.SizeofSockaddrInet6])
 			ip := make(IP, IPv6len)
 			copy(ip, sa.Addr[:])
+			if ip4 := ip.To4(); ip4 != nil {
+				// An IPv4 peer of a dual-stack socket with
+				// SCTP_I_WANT_MAPPED_V4_ADDR enabled.
+				out = append(out, SCTPAddr{IP: ip4, Port: int(ntohs(sa.Port))})
+				data = data[syscall.SizeofSockaddrInet6:]
+				continue
+			}
 			out = append(out, SCTPAddr{
 				IP:   ip,
 				Port: int(ntohs(sa.Port)),
@@ -343,7 +1123,13 @@ func parseRawSockaddrsSCTP(data []byte, n int) ([]SCTPAddr, error) {
 func marshalRawSockaddrsSCTP(family int, addrs []SCTPAddr) ([]byte, error) {
 	buf := make([]byte, 0, len(addrs)*syscall.SizeofSockaddrInet6)
 	for i := range addrs {
-		sa, err := addrs[i].sockaddr(family)
+		// IPv4 entries stay sockaddr_in even on an AF_INET6 socket;
+		// the kernel accepts both families in one packed list.
+		af := family
+		if addrs[i].IP.To4() != nil {
+			af = syscall.AF_INET
+		}
+		sa, err := addrs[i].sockaddr(af)
 		if err != nil {
 			return nil, err
 		}
@@ -7,11 +7,22 @@
 package net
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
+	"unsafe"
 )
 
 const sctpMsgNotification = 0x8000
@@ -95,6 +106,15 @@ func TestSCTPLoopbackReadWrite(t *testing.T) {
 	if info.Stream != snd.Stream {
 		t.Fatalf("ReadFromSCTP stream=%d; want %d", info.Stream, snd.Stream)
 	}
+
+	want := SCTPStreamStats{Stream: 2, PPID: 42, MessagesOut: 1, BytesOut: uint64(len(payload))}
+	if s := cli.Stats(); len(s.Streams) != 1 || s.Streams[0] != want {
+		t.Errorf("client Stats().Streams = %+v; want [%+v]", s.Streams, want)
+	}
+	want = SCTPStreamStats{Stream: 2, PPID: 42, MessagesIn: 1, BytesIn: uint64(len(payload))}
+	if s := srv.Stats(); len(s.Streams) != 1 || s.Streams[0] != want || s.Notifications[sctpEventAssociation] == 0 {
+		t.Errorf("server Stats() = %+v; want streams [%+v] and SCTP_ASSOC_CHANGE counted", s, want)
+	}
 }
 
 func TestSCTPUnsupportedOnBadNetwork(t *testing.T) {
@@ -232,6 +252,187 @@ func TestDialSCTPMultiPeerAddrs(t *testing.T) {
 	}
 }
 
+func TestDialSCTPMultihomeHostname(t *testing.T) {
+	requireSCTP(t)
+
+	origTestHookLookupIP := testHookLookupIP
+	defer func() { testHookLookupIP = origTestHookLookupIP }()
+	testHookLookupIP = lookupSCTPPeers
+
+	// Every record is used, including the IPv6 one when the
+	// association can be dual-stack.
+	network := "sctp4"
+	laddr := &SCTPMultiAddr{
+		Addrs: []SCTPAddr{
+			{IP: IPv4(127, 0, 0, 1), Port: 0},
+			{IP: IPv4(127, 0, 0, 2), Port: 0},
+		},
+	}
+	want := []string{"127.0.0.1", "127.0.0.2"}
+	if supportsIPv4map() {
+		network = "sctp"
+		laddr.Addrs = append(laddr.Addrs, SCTPAddr{IP: IPv6loopback})
+		want = append(want, "::1")
+	}
+	srv, err := ListenSCTPMulti(network, laddr)
+	if err != nil {
+		t.Skipf("multihome listen unavailable: %v", err)
+	}
+	defer srv.Close()
+	sla := srv.LocalAddr().(*SCTPAddr)
+
+	var d Dialer
+	d.SetMultihomeSCTP(true)
+	c, err := d.Dial("sctp", JoinHostPort("sctp-peers.test", strconv.Itoa(sla.Port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	cli := c.(*SCTPConn)
+
+	if _, err := cli.WriteToSCTP([]byte("hello"), nil, &SCTPSndInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 256)
+	for {
+		_, _, flags, _, _, err := srv.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flags&sctpMsgNotification == 0 {
+			break
+		}
+	}
+
+	got, err := peerAddrsSCTP(cli.fd, cli.assocID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ips []string
+	for _, a := range got {
+		ips = append(ips, a.IP.String())
+	}
+	slices.Sort(ips)
+	if !slices.Equal(ips, want) {
+		t.Fatalf("kernel peer addresses = %v; want %v", ips, want)
+	}
+}
+
+func TestSCTPRawSockaddrsMixedFamilies(t *testing.T) {
+	ifi := loopbackInterface()
+	if ifi == nil {
+		t.Skip("loopback interface not found")
+	}
+	in := []SCTPAddr{
+		{IP: IPv4(192, 0, 2, 1), Port: 9000},
+		{IP: ParseIP("fe80::1"), Port: 9000, Zone: ifi.Name},
+		{IP: ParseIP("2001:db8::1"), Port: 9000},
+	}
+	b, err := marshalRawSockaddrsSCTP(syscall.AF_INET6, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// IPv4 entries are packed as sockaddr_in, not as IPv4-mapped
+	// sockaddr_in6.
+	if want := syscall.SizeofSockaddrInet4 + 2*syscall.SizeofSockaddrInet6; len(b) != want {
+		t.Fatalf("marshaled %d bytes; want %d", len(b), want)
+	}
+	if fam := *(*uint16)(unsafe.Pointer(&b[0])); fam != syscall.AF_INET {
+		t.Fatalf("first entry family = %d; want AF_INET", fam)
+	}
+	sa6 := (*syscall.RawSockaddrInet6)(unsafe.Pointer(&b[syscall.SizeofSockaddrInet4]))
+	if int(sa6.Scope_id) != ifi.Index {
+		t.Errorf("link-local scope id = %d; want %d", sa6.Scope_id, ifi.Index)
+	}
+
+	out, err := parseRawSockaddrsSCTP(b, len(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := (&SCTPMultiAddr{Addrs: out}).String(), (&SCTPMultiAddr{Addrs: in}).String(); got != want {
+		t.Errorf("round trip = %s; want %s", got, want)
+	}
+
+	// A kernel reporting IPv4-mapped addresses yields native IPv4.
+	mapped := syscall.RawSockaddrInet6{Family: syscall.AF_INET6, Port: htons(9000)}
+	copy(mapped.Addr[:], IPv4(192, 0, 2, 1).To16())
+	out, err = parseRawSockaddrsSCTP(unsafe.Slice((*byte)(unsafe.Pointer(&mapped)), syscall.SizeofSockaddrInet6), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || len(out[0].IP) != IPv4len || out[0].Port != 9000 {
+		t.Errorf("parsed mapped address = %v; want 192.0.2.1:9000 as IPv4", out)
+	}
+}
+
+func TestDialSCTPMultiMixedFamilies(t *testing.T) {
+	requireSCTP(t)
+	if !supportsIPv6() || !supportsIPv4map() {
+		t.Skip("dual-stack sockets unavailable")
+	}
+
+	srv, err := ListenSCTPMulti("sctp", &SCTPMultiAddr{
+		Addrs: []SCTPAddr{
+			{IP: IPv4(127, 0, 0, 1)},
+			{IP: IPv6loopback},
+		},
+	})
+	if err != nil {
+		t.Skipf("dual-stack listen unavailable: %v", err)
+	}
+	defer srv.Close()
+	port := srv.LocalAddr().(*SCTPAddr).Port
+
+	cli, err := DialSCTPMulti("sctp", nil, &SCTPMultiAddr{
+		Addrs: []SCTPAddr{
+			{IP: IPv4(127, 0, 0, 1), Port: port},
+			{IP: IPv6loopback, Port: port},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	if cli.fd.family != syscall.AF_INET6 {
+		t.Fatalf("client socket family = %d; want AF_INET6", cli.fd.family)
+	}
+
+	if _, err := cli.WriteToSCTP([]byte("hello"), nil, &SCTPSndInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	srv.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 256)
+	for {
+		_, _, flags, _, _, err := srv.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if flags&sctpMsgNotification == 0 {
+			break
+		}
+	}
+
+	got, err := peerAddrsSCTP(cli.fd, cli.assocID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v4, v6 bool
+	for _, a := range got {
+		switch {
+		case len(a.IP) == IPv4len:
+			v4 = true
+		case a.IP.To4() != nil:
+			t.Errorf("peer %v reported as IPv4-mapped IPv6", a.IP)
+		default:
+			v6 = true
+		}
+	}
+	if !v4 || !v6 {
+		t.Errorf("kernel peer addresses = %v; want one IPv4 and one IPv6", got)
+	}
+}
+
 func TestDialSCTPMultiWriteFallback(t *testing.T) {
 	requireSCTP(t)
 
@@ -287,3 +488,1259 @@ func TestDialSCTPMultiWriteFallback(t *testing.T) {
 		break
 	}
 }
+
+func TestSCTPAssocStats(t *testing.T) {
+	requireSCTP(t)
+
+	srv, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer srv.Close()
+	if err := srv.SetInitOptions(SCTPInitOptions{NumOStreams: 4, MaxInStreams: 4}); err != nil {
+		t.Fatalf("SetInitOptions(server) error: %v", err)
+	}
+
+	cli, err := DialSCTP("sctp4", nil, srv.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+
+	if _, err := cli.WriteToSCTP([]byte("sctp-stats"), nil, &SCTPSndInfo{Stream: 1}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+	if err := srv.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline(server) error: %v", err)
+	}
+	buf := make([]byte, 256)
+	var info *SCTPRcvInfo
+	for {
+		_, _, flags, _, ri, err := srv.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP error: %v", err)
+		}
+		if flags&sctpMsgNotification != 0 {
+			continue
+		}
+		info = ri
+		break
+	}
+	if info == nil {
+		t.Fatalf("ReadFromSCTP info=nil; want SCTP_RCVINFO")
+	}
+
+	st, err := srv.AssocStats(info.AssocID)
+	if err != nil {
+		t.Skipf("SCTP_GET_ASSOC_STATS unavailable: %v", err)
+	}
+	if st.InPackets == 0 {
+		t.Errorf("AssocStats InPackets=0; want >0")
+	}
+	if st.InOrderedChunks == 0 {
+		t.Errorf("AssocStats InOrderedChunks=0; want >0")
+	}
+}
+
+// TestSCTPKernelStructLayout checks the structures whose layout
+// depends on the alignment of struct sockaddr_storage and __u64
+// against the sizes and offsets of the kernel's.
+func TestSCTPKernelStructLayout(t *testing.T) {
+	// 64-bit systems.
+	statsSize, statsMaxRTO, ssOff, ssSize := uintptr(256), uintptr(136), uintptr(8), uintptr(144)
+	switch runtime.GOARCH {
+	case "386":
+		statsSize, statsMaxRTO, ssOff, ssSize = 252, 132, 4, 136
+	case "arm", "mips", "mipsle":
+		statsSize, statsMaxRTO, ssOff, ssSize = 256, 136, 4, 136
+	}
+	var st sctpAssocStats
+	var ue sctpUDPEncaps
+	var pi sctpProbeInterval
+	for _, tt := range []struct {
+		name      string
+		got, want uintptr
+	}{
+		{"sizeof(sctp_assoc_stats)", unsafe.Sizeof(st), statsSize},
+		{"offsetof(sctp_assoc_stats, sas_obs_rto_ipaddr)", unsafe.Offsetof(st.ObsRTOAddr), ssOff},
+		{"offsetof(sctp_assoc_stats, sas_maxrto)", unsafe.Offsetof(st.MaxRTO), statsMaxRTO},
+		{"offsetof(sctp_assoc_stats, sas_ictrlchunks)", unsafe.Offsetof(st.ICtrlChunks), statsSize - 8},
+		{"sizeof(sctp_udpencaps)", unsafe.Sizeof(ue), ssSize},
+		{"offsetof(sctp_udpencaps, sue_address)", unsafe.Offsetof(ue.Address), ssOff},
+		{"offsetof(sctp_udpencaps, sue_port)", unsafe.Offsetof(ue.Port), ssOff + 128},
+		{"sizeof(sctp_probeinterval)", unsafe.Sizeof(pi), ssSize},
+		{"offsetof(sctp_probeinterval, spi_address)", unsafe.Offsetof(pi.Address), ssOff},
+		{"offsetof(sctp_probeinterval, spi_interval)", unsafe.Offsetof(pi.Interval), ssOff + 128},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d; want %d", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSCTPRemoteUDPEncapsPort(t *testing.T) {
+	requireSCTP(t)
+	if _, err := SCTPUDPEncapsPort(); err != nil {
+		t.Skipf("SCTP UDP encapsulation unavailable: %v", err)
+	}
+
+	c, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.SetRemoteUDPEncapsPort(0, nil, 9899); err != nil {
+		t.Skipf("SCTP_REMOTE_UDP_ENCAPS_PORT unavailable: %v", err)
+	}
+	port, err := c.RemoteUDPEncapsPort(0, nil)
+	if err != nil {
+		t.Fatalf("RemoteUDPEncapsPort error: %v", err)
+	}
+	if port != 9899 {
+		t.Fatalf("RemoteUDPEncapsPort=%d; want 9899", port)
+	}
+}
+
+func TestSCTPMessageSizeError(t *testing.T) {
+	requireSCTP(t)
+
+	srv, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer srv.Close()
+
+	cli, err := DialSCTP("sctp4", nil, srv.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+
+	if err := cli.SetWriteBuffer(4096); err != nil {
+		t.Fatalf("SetWriteBuffer error: %v", err)
+	}
+	limit, err := cli.MaxMessageSize(0)
+	if err != nil {
+		t.Fatalf("MaxMessageSize error: %v", err)
+	}
+	_, err = cli.WriteToSCTP(make([]byte, limit+1), nil, &SCTPSndInfo{Stream: 0})
+	if err == nil {
+		t.Skipf("kernel accepted a %d byte message with a %d byte send buffer", limit+1, limit)
+	}
+	var serr *SCTPMessageSizeError
+	if !errors.As(err, &serr) {
+		t.Fatalf("WriteToSCTP error=%v; want SCTPMessageSizeError", err)
+	}
+	if serr.Len != limit+1 || serr.Max != limit {
+		t.Fatalf("SCTPMessageSizeError=%+v; want Len=%d Max=%d", serr, limit+1, limit)
+	}
+	if !errors.Is(err, syscall.EMSGSIZE) {
+		t.Fatalf("WriteToSCTP error=%v; want errors.Is EMSGSIZE", err)
+	}
+}
+
+func TestSCTPFeatures(t *testing.T) {
+	requireSCTP(t)
+
+	c, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer c.Close()
+
+	want := SCTPFeatures{ECN: false, PartialReliability: true}
+	if err := c.SetFeatures(want); err != nil {
+		t.Skipf("SCTP extension socket options unavailable: %v", err)
+	}
+	got, err := c.Features()
+	if err != nil {
+		t.Fatalf("Features error: %v", err)
+	}
+	if got != want {
+		t.Fatalf("Features=%+v; want %+v", got, want)
+	}
+
+	// Features set on the ListenConfig and Dialer apply before the
+	// first association, and are reported per association.
+	var lc ListenConfig
+	lc.SetFeaturesSCTP(want)
+	pc, err := lc.ListenPacket(context.Background(), "sctp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket error: %v", err)
+	}
+	defer pc.Close()
+	ln, err := NewSCTPListener(pc.(*SCTPConn))
+	if err != nil {
+		t.Fatalf("NewSCTPListener error: %v", err)
+	}
+	if got, err := ln.Conn().Features(); err != nil || got != want {
+		t.Fatalf("listener Features = %+v, %v; want %+v", got, err, want)
+	}
+	var d Dialer
+	d.SetFeaturesSCTP(want)
+	if f, ok := d.FeaturesSCTP(); !ok || f != want {
+		t.Fatalf("FeaturesSCTP = %+v, %v; want %+v, true", f, ok, want)
+	}
+	dc, err := d.Dial("sctp4", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer dc.Close()
+	cli := dc.(*SCTPConn)
+	if got, err := cli.Features(); err != nil || got != want {
+		t.Fatalf("dialer Features = %+v, %v; want %+v", got, err, want)
+	}
+	if _, err := cli.Write([]byte("x")); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	ln.SetDeadline(time.Now().Add(5 * time.Second))
+	srv, err := ln.AcceptSCTP()
+	if err != nil {
+		t.Fatalf("AcceptSCTP error: %v", err)
+	}
+	defer srv.Close()
+	if got, err := srv.PeerFeatures(0); err != nil || got.ECN || !got.PartialReliability {
+		t.Fatalf("PeerFeatures = %+v, %v; want PR-SCTP without ECN", got, err)
+	}
+}
+
+func TestParseSCTPNotification(t *testing.T) {
+	ae := sctpAdaptationEvent{
+		sctpNotificationHeader: sctpNotificationHeader{Type: sctpEventAdaptation, Length: uint32(sizeofSCTPAdaptationEvent)},
+		Indication:             0x1234,
+		AssocID:                7,
+	}
+	n, err := ParseSCTPNotification(unsafe.Slice((*byte)(unsafe.Pointer(&ae)), sizeofSCTPAdaptationEvent))
+	if err != nil {
+		t.Fatalf("ParseSCTPNotification error: %v", err)
+	}
+	got, ok := n.(*SCTPAdaptationEvent)
+	if !ok {
+		t.Fatalf("ParseSCTPNotification type=%T; want *SCTPAdaptationEvent", n)
+	}
+	if got.Indication != 0x1234 || got.AssocID != 7 {
+		t.Fatalf("ParseSCTPNotification=%+v; want indication 0x1234 assoc 7", got)
+	}
+
+	ac := sctpAssocChange{
+		sctpNotificationHeader: sctpNotificationHeader{Type: sctpEventAssociation, Length: uint32(sizeofSCTPAssocChange)},
+		State:                  uint16(SCTPCommLost),
+		AssocID:                7,
+	}
+	c := &SCTPConn{adaptation: map[int32]uint32{7: 0x1234}}
+	c.observeNotification(unsafe.Slice((*byte)(unsafe.Pointer(&ac)), sizeofSCTPAssocChange))
+	if _, ok := c.adaptation[7]; ok {
+		t.Fatalf("adaptation indication kept after SCTP_COMM_LOST")
+	}
+
+	if _, err := ParseSCTPNotification([]byte{1, 2}); err == nil {
+		t.Fatalf("ParseSCTPNotification(short) error=nil")
+	}
+}
+
+// sctpPaddrChangeBytes returns a struct sctp_paddr_change for addr.
+func sctpPaddrChangeBytes(t *testing.T, addr SCTPAddr, state SCTPPeerAddrState, assocID int32) []byte {
+	t.Helper()
+	raw, err := marshalRawSockaddrsSCTP(syscall.AF_INET6, []SCTPAddr{addr})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pc := sctpPaddrChange{
+		sctpNotificationHeader: sctpNotificationHeader{Type: sctpEventAddress, Length: uint32(sizeofSCTPPaddrChange)},
+		State:                  int32(state),
+		AssocID:                assocID,
+	}
+	copy(pc.Address[:], raw)
+	return unsafe.Slice((*byte)(unsafe.Pointer(&pc)), sizeofSCTPPaddrChange)
+}
+
+func TestSCTPPeerPathTracking(t *testing.T) {
+	a1 := SCTPAddr{IP: IPv4(192, 0, 2, 1), Port: 3868}
+	a2 := SCTPAddr{IP: ParseIP("2001:db8::2"), Port: 3868}
+	a3 := SCTPAddr{IP: IPv4(192, 0, 2, 3), Port: 3868}
+
+	n, err := ParseSCTPNotification(sctpPaddrChangeBytes(t, a2, SCTPAddrUnreachable, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, ok := n.(*SCTPPeerAddrChangeEvent)
+	if !ok || !ev.Addr.equal(&a2) || ev.State != SCTPAddrUnreachable || ev.AssocID != 7 {
+		t.Fatalf("ParseSCTPNotification = %+v; want %v unreachable on assoc 7", n, a2)
+	}
+
+	c := &SCTPConn{
+		multiPeer: []SCTPAddr{a1, a2},
+		paths: map[int32][]SCTPPath{7: {
+			{Addr: a1, State: SCTPAddrAvailable, Primary: true},
+			{Addr: a2, State: SCTPAddrAvailable},
+		}},
+	}
+	var got []SCTPPath
+	c.pathHandler = func(assocID int32, p SCTPPath) {
+		if assocID != 7 {
+			t.Errorf("handler called for assoc %d; want 7", assocID)
+		}
+		got = append(got, p)
+	}
+	for _, e := range []struct {
+		addr  SCTPAddr
+		state SCTPPeerAddrState
+	}{
+		{a1, SCTPAddrUnreachable},
+		{a2, SCTPAddrMadePrimary},
+		{a3, SCTPAddrAdded},
+		{a3, SCTPAddrConfirmed},
+		{a1, SCTPAddrRemoved},
+	} {
+		c.observeNotification(sctpPaddrChangeBytes(t, e.addr, e.state, 7))
+	}
+
+	want := []SCTPPath{
+		{Addr: a2, State: SCTPAddrAvailable, Primary: true},
+		{Addr: a3, State: SCTPAddrConfirmed},
+	}
+	paths := c.paths[7]
+	if len(paths) != len(want) {
+		t.Fatalf("paths = %+v; want %+v", paths, want)
+	}
+	for i := range want {
+		if !paths[i].Addr.equal(&want[i].Addr) || paths[i].State != want[i].State || paths[i].Primary != want[i].Primary {
+			t.Errorf("paths[%d] = %+v; want %+v", i, paths[i], want[i])
+		}
+	}
+	if len(got) != 5 || got[0].State != SCTPAddrUnreachable || !got[1].Primary || got[4].State != SCTPAddrRemoved {
+		t.Errorf("handler saw %+v", got)
+	}
+	if peers := (&SCTPMultiAddr{Addrs: c.multiPeer}).String(); peers != "[2001:db8::2]:3868,192.0.2.3:3868" {
+		t.Errorf("multiPeer = %s; want [2001:db8::2]:3868,192.0.2.3:3868", peers)
+	}
+
+	ac := sctpAssocChange{
+		sctpNotificationHeader: sctpNotificationHeader{Type: sctpEventAssociation, Length: uint32(sizeofSCTPAssocChange)},
+		State:                  uint16(SCTPCommLost),
+		AssocID:                7,
+	}
+	c.observeNotification(unsafe.Slice((*byte)(unsafe.Pointer(&ac)), sizeofSCTPAssocChange))
+	if _, ok := c.paths[7]; ok {
+		t.Error("paths kept after SCTP_COMM_LOST")
+	}
+}
+
+func TestSCTPAdaptationLayer(t *testing.T) {
+	requireSCTP(t)
+
+	srv, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer srv.Close()
+	if err := srv.SubscribeEvents(SCTPEventMask{Association: true, Adaptation: true}); err != nil {
+		t.Fatalf("SubscribeEvents(server) error: %v", err)
+	}
+
+	cli, err := DialSCTP("sctp4", nil, srv.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	if err := cli.SetAdaptationLayer(0xcafe); err != nil {
+		t.Fatalf("SetAdaptationLayer error: %v", err)
+	}
+	if ind, err := cli.AdaptationLayer(); err != nil || ind != 0xcafe {
+		t.Fatalf("AdaptationLayer=%#x, %v; want 0xcafe", ind, err)
+	}
+	if _, err := cli.WriteToSCTP([]byte("sctp-adaptation"), nil, &SCTPSndInfo{}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+
+	if err := srv.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline(server) error: %v", err)
+	}
+	buf := make([]byte, 256)
+	for {
+		n, _, flags, _, _, err := srv.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP error: %v", err)
+		}
+		if flags&SCTPMsgNotification == 0 {
+			continue
+		}
+		ev, err := ParseSCTPNotification(buf[:n])
+		if err != nil {
+			t.Fatalf("ParseSCTPNotification error: %v", err)
+		}
+		if ae, ok := ev.(*SCTPAdaptationEvent); ok {
+			if ae.Indication != 0xcafe {
+				t.Fatalf("SCTPAdaptationEvent.Indication=%#x; want 0xcafe", ae.Indication)
+			}
+			if ind, ok := srv.PeerAdaptationLayer(ae.AssocID); !ok || ind != 0xcafe {
+				t.Fatalf("PeerAdaptationLayer=%#x, %v; want 0xcafe, true", ind, ok)
+			}
+			return
+		}
+	}
+}
+
+func TestSCTPDrainCloseWrite(t *testing.T) {
+	requireSCTP(t)
+
+	srv, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer srv.Close()
+	if err := srv.SubscribeEvents(SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents(server) error: %v", err)
+	}
+
+	cli, err := DialSCTP("sctp4", nil, srv.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+
+	payload := []byte("sctp-final-message")
+	if _, err := cli.WriteToSCTP(payload, nil, &SCTPSndInfo{Stream: 1}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := cli.Drain(ctx); err != nil {
+		t.Fatalf("Drain error: %v", err)
+	}
+	if err := cli.CloseWrite(); err != nil {
+		t.Fatalf("CloseWrite error: %v", err)
+	}
+
+	if err := srv.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline(server) error: %v", err)
+	}
+	buf := make([]byte, 256)
+	var gotPayload bool
+	for {
+		n, _, flags, _, _, err := srv.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP error: %v (payload received: %v)", err, gotPayload)
+		}
+		if flags&SCTPMsgNotification == 0 {
+			if !bytes.Equal(buf[:n], payload) {
+				t.Fatalf("payload mismatch got %q want %q", buf[:n], payload)
+			}
+			gotPayload = true
+			continue
+		}
+		ev, err := ParseSCTPNotification(buf[:n])
+		if err != nil {
+			t.Fatalf("ParseSCTPNotification error: %v", err)
+		}
+		if ac, ok := ev.(*SCTPAssocChangeEvent); ok && ac.State == SCTPShutdownComplete {
+			break
+		}
+	}
+	if !gotPayload {
+		t.Fatalf("association shut down before payload was delivered")
+	}
+}
+
+func TestSCTPAutoClose(t *testing.T) {
+	requireSCTP(t)
+
+	c, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.SetAutoClose(1500 * time.Millisecond); err != nil {
+		t.Fatalf("SetAutoClose error: %v", err)
+	}
+	got, err := c.AutoClose()
+	if err != nil {
+		t.Fatalf("AutoClose error: %v", err)
+	}
+	if got != 2*time.Second {
+		t.Fatalf("AutoClose = %v; want %v", got, 2*time.Second)
+	}
+	if err := c.SetAutoClose(-time.Second); err == nil {
+		t.Fatal("SetAutoClose(-1s) succeeded; want error")
+	}
+}
+
+func TestSCTPIdleTimeout(t *testing.T) {
+	requireSCTP(t)
+
+	srv, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer srv.Close()
+
+	reaped := make(chan int32, 1)
+	if err := srv.SetIdleTimeout(100*time.Millisecond, func(id int32) { reaped <- id }); err != nil {
+		t.Fatalf("SetIdleTimeout error: %v", err)
+	}
+
+	cli, err := DialSCTP("sctp4", nil, srv.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	if _, err := cli.WriteToSCTP([]byte("idle"), nil, &SCTPSndInfo{Stream: 1}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+
+	if err := srv.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline error: %v", err)
+	}
+	buf := make([]byte, 256)
+	_, _, _, _, info, err := srv.ReadFromSCTP(buf)
+	if err != nil {
+		t.Fatalf("ReadFromSCTP error: %v", err)
+	}
+	if info == nil || info.AssocID == 0 {
+		t.Fatalf("ReadFromSCTP info = %+v; want association ID", info)
+	}
+
+	select {
+	case id := <-reaped:
+		if id != info.AssocID {
+			t.Fatalf("reaped association %d; want %d", id, info.AssocID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("idle association was not reaped")
+	}
+
+	// Close stops the reaper, and a run already in progress does not
+	// re-arm it.
+	srv.Close()
+	srv.mu.Lock()
+	idle := srv.idle
+	srv.mu.Unlock()
+	if idle != nil {
+		t.Fatal("idle reaper still set after Close")
+	}
+}
+
+type sctpDiscardWriter struct{}
+
+func (w *sctpDiscardWriter) Write(b []byte) (int, error) { return len(b), nil }
+
+func (w *sctpDiscardWriter) WriteMessage(b []byte, info SCTPSndInfo) (int, error) {
+	return len(b), nil
+}
+
+func (w *sctpDiscardWriter) Conn() *SCTPConn { return nil }
+
+func TestSCTPServeMux(t *testing.T) {
+	var got []string
+	mux := NewSCTPServeMux()
+	mux.HandleFunc(46, func(w SCTPResponseWriter, m *SCTPMessage) { got = append(got, "diameter") })
+	mux.HandleFunc(60, func(w SCTPResponseWriter, m *SCTPMessage) { got = append(got, "ngap") })
+
+	w := new(sctpDiscardWriter)
+	for _, ppid := range []uint32{60, 46, 7} {
+		mux.ServeSCTP(w, &SCTPMessage{Info: SCTPRcvInfo{PPID: ppid}})
+	}
+	if want := []string{"ngap", "diameter"}; !slices.Equal(got, want) {
+		t.Fatalf("handled %q; want %q", got, want)
+	}
+
+	mux.HandleDefault(SCTPHandlerFunc(func(w SCTPResponseWriter, m *SCTPMessage) { got = append(got, "default") }))
+	mux.ServeSCTP(w, &SCTPMessage{Info: SCTPRcvInfo{PPID: 7}})
+	if got[len(got)-1] != "default" {
+		t.Fatalf("unregistered PPID handled by %q; want default", got[len(got)-1])
+	}
+}
+
+func TestSCTPServer(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	mux := NewSCTPServeMux()
+	mux.HandleFunc(42, func(w SCTPResponseWriter, m *SCTPMessage) {
+		w.Write(bytes.ToUpper(m.Data))
+	})
+	srv := &SCTPServer{Handler: mux}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+
+	const count = 8
+	for i := 0; i < count; i++ {
+		msg := []byte{'a' + byte(i)}
+		if _, err := cli.WriteToSCTP(msg, nil, &SCTPSndInfo{Stream: 1, PPID: 42}); err != nil {
+			t.Fatalf("WriteToSCTP error: %v", err)
+		}
+	}
+	if err := cli.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline error: %v", err)
+	}
+	buf := make([]byte, 64)
+	for i := 0; i < count; i++ {
+		n, _, flags, _, _, err := cli.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP error: %v", err)
+		}
+		if flags&SCTPMsgNotification != 0 {
+			i--
+			continue
+		}
+		if want := string(rune('A' + i)); string(buf[:n]) != want {
+			t.Fatalf("reply %d = %q; want %q", i, buf[:n], want)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if err := <-served; err != ErrSCTPServerClosed {
+		t.Fatalf("Serve returned %v; want %v", err, ErrSCTPServerClosed)
+	}
+}
+
+func TestSCTPServerMaxPending(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	var running, peak atomic.Int32
+	started := make(chan struct{}, 8)
+	release := make(chan struct{})
+	srv := &SCTPServer{
+		MaxPending: 2,
+		Handler: SCTPHandlerFunc(func(w SCTPResponseWriter, m *SCTPMessage) {
+			n := running.Add(1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			started <- struct{}{}
+			<-release
+			running.Add(-1)
+		}),
+	}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	for i := 0; i < 5; i++ {
+		if _, err := cli.WriteToSCTP([]byte{byte(i)}, nil, &SCTPSndInfo{Stream: uint16(i), Flags: SCTPUnordered}); err != nil {
+			t.Fatalf("WriteToSCTP error: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		<-started
+	}
+	select {
+	case <-started:
+		t.Fatal("handler started past MaxPending")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown error: %v", err)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d handlers ran at once; want at most 2", p)
+	}
+	// The socket is closed by the time Shutdown returns.
+	if _, err := ln.WriteToSCTP([]byte("x"), cli.LocalAddr().(*SCTPAddr), nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteToSCTP after Shutdown error = %v; want %v", err, ErrClosed)
+	}
+	if err := <-served; err != ErrSCTPServerClosed {
+		t.Fatalf("Serve returned %v; want %v", err, ErrSCTPServerClosed)
+	}
+}
+
+func TestSCTPMessagesAndNotifications(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SubscribeEvents(SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+	events := ln.Notifications()
+	commUp := make(chan *SCTPAssocChangeEvent, 1)
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		for nt := range events {
+			if ac, ok := nt.(*SCTPAssocChangeEvent); ok && ac.State == SCTPCommUp {
+				select {
+				case commUp <- ac:
+				default:
+				}
+			}
+		}
+	}()
+
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	want := []string{"one", "two", "three"}
+	for i, msg := range want {
+		if _, err := cli.WriteToSCTP([]byte(msg), nil, &SCTPSndInfo{Stream: uint16(i), PPID: 42}); err != nil {
+			t.Fatalf("WriteToSCTP error: %v", err)
+		}
+	}
+
+	if err := ln.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetReadDeadline error: %v", err)
+	}
+	var got []string
+	for m, err := range ln.Messages() {
+		if err != nil {
+			t.Fatalf("Messages error: %v", err)
+		}
+		if m.Info.PPID != 42 || int(m.Info.Stream) != len(got) {
+			t.Errorf("message %d info = %+v; want stream %d, PPID 42", len(got), m.Info, len(got))
+		}
+		got = append(got, string(m.Data))
+		if len(got) == len(want) {
+			break
+		}
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Messages = %q; want %q", got, want)
+	}
+	select {
+	case <-commUp:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no SCTP_COMM_UP notification")
+	}
+
+	if err := ln.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	for m, err := range ln.Messages() {
+		t.Fatalf("Messages after Close yielded %v, %v", m, err)
+	}
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notifications channel not closed after Close")
+	}
+}
+
+func TestSCTPMessagesDeadline(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SetReadDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+		t.Fatalf("SetReadDeadline error: %v", err)
+	}
+	for range 2 {
+		var errs []error
+		for m, err := range ln.Messages() {
+			if m != nil {
+				t.Fatalf("Messages yielded unexpected message %q", m.Data)
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) != 1 || !errors.Is(errs[0], os.ErrDeadlineExceeded) {
+			t.Fatalf("Messages errors = %v; want one %v", errs, os.ErrDeadlineExceeded)
+		}
+	}
+}
+
+func TestParseSCTPStreamResetEvent(t *testing.T) {
+	b := make([]byte, sizeofSCTPStreamResetEvent+4)
+	re := (*sctpStreamResetEvent)(unsafe.Pointer(&b[0]))
+	re.Type = sctpEventStreamReset
+	re.Flags = SCTPStreamResetIncoming
+	re.Length = uint32(len(b))
+	re.AssocID = 7
+	*(*uint16)(unsafe.Pointer(&b[sizeofSCTPStreamResetEvent])) = 3
+	*(*uint16)(unsafe.Pointer(&b[sizeofSCTPStreamResetEvent+2])) = 5
+
+	n, err := ParseSCTPNotification(b)
+	if err != nil {
+		t.Fatalf("ParseSCTPNotification error: %v", err)
+	}
+	got, ok := n.(*SCTPStreamResetEvent)
+	if !ok {
+		t.Fatalf("ParseSCTPNotification type=%T; want *SCTPStreamResetEvent", n)
+	}
+	if got.Flags != SCTPStreamResetIncoming || got.AssocID != 7 || !slices.Equal(got.Streams, []uint16{3, 5}) {
+		t.Fatalf("ParseSCTPNotification=%+v; want incoming reset of streams [3 5] on assoc 7", got)
+	}
+}
+
+func TestParseSCTPSendFailedEvent(t *testing.T) {
+	payload := []byte("unsent")
+	b := make([]byte, sizeofSCTPSendFailedEvent+len(payload))
+	sf := (*sctpSendFailedEvent)(unsafe.Pointer(&b[0]))
+	sf.Type = sctpEventSendFailedEvent
+	sf.Length = uint32(len(b))
+	sf.Error = 12
+	sf.Info = sctpSndInfoLinux{Stream: 2, PPID: 46, Context: 77, AssocID: 7}
+	sf.AssocID = 7
+	copy(b[sizeofSCTPSendFailedEvent:], payload)
+
+	// The deprecated SCTP_SEND_FAILED layout, as a peer-acknowledged
+	// but abandoned message.
+	old := make([]byte, sizeofSCTPSendFailed+len(payload))
+	of := (*sctpSendFailed)(unsafe.Pointer(&old[0]))
+	of.Type = sctpEventSendFailure
+	of.Flags = sctpDataSent
+	of.Length = uint32(len(old))
+	of.Error = 12
+	of.Info = sctpSndRcvInfo{Stream: 2, PPID: 46, Context: 77, AssocID: 7}
+	of.AssocID = 7
+	copy(old[sizeofSCTPSendFailed:], payload)
+
+	want := SCTPSndInfo{Stream: 2, PPID: 46, Context: 77, AssocID: 7}
+	for _, tt := range []struct {
+		b      []byte
+		unsent bool
+	}{{b, true}, {old, false}} {
+		n, err := ParseSCTPNotification(tt.b)
+		if err != nil {
+			t.Fatalf("ParseSCTPNotification error: %v", err)
+		}
+		got, ok := n.(*SCTPSendFailedEvent)
+		if !ok {
+			t.Fatalf("ParseSCTPNotification type=%T; want *SCTPSendFailedEvent", n)
+		}
+		if got.Info != want || string(got.Payload) != "unsent" || got.Error != 12 || got.Unsent != tt.unsent {
+			t.Errorf("ParseSCTPNotification=%+v; want info %+v, payload %q, error 12, unsent %v", got, want, payload, tt.unsent)
+		}
+	}
+}
+
+// TestSCTPResend checks that a message returned by a send failure can
+// be sent again on another association.
+func TestSCTPResend(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SetRecvRcvInfo(true); err != nil {
+		t.Fatalf("SetRecvRcvInfo error: %v", err)
+	}
+	if err := ln.SetAssocContext(0, 1234); err != nil {
+		t.Fatalf("SetAssocContext error: %v", err)
+	}
+	if v, err := ln.AssocContext(0); err != nil || v != 1234 {
+		t.Fatalf("AssocContext = %d, %v; want 1234", v, err)
+	}
+
+	cli, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer cli.Close()
+	ev := &SCTPSendFailedEvent{
+		Info:    SCTPSndInfo{Stream: 1, PPID: 46, Context: 77, AssocID: 99},
+		Payload: []byte("relayed"),
+		Unsent:  true,
+	}
+	if _, err := cli.Resend(ev, 0, ln.LocalAddr().(*SCTPAddr)); err != nil {
+		t.Fatalf("Resend error: %v", err)
+	}
+
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 64)
+	for {
+		n, _, flags, _, info, err := ln.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP error: %v", err)
+		}
+		if flags&SCTPMsgNotification != 0 {
+			continue
+		}
+		if string(buf[:n]) != "relayed" || info == nil || info.Stream != 1 || info.PPID != 46 || info.Context != 1234 {
+			t.Fatalf("read %q, %+v; want %q on stream 1, PPID 46, context 1234", buf[:n], info, "relayed")
+		}
+		break
+	}
+}
+
+func TestSCTPStream(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SubscribeEvents(SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+	lnStream := ln.Stream(0, 3, 99)
+
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	if err := cli.SubscribeEvents(SCTPEventMask{Association: true, StreamReset: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+	cliStream := cli.Stream(0, 3, 99)
+
+	// A message on another stream is not seen by either handle.
+	if _, err := cli.WriteToSCTP([]byte("other"), nil, &SCTPSndInfo{Stream: 4, PPID: 99}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+	if _, err := cliStream.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	lnStream.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 3)
+	var got []byte
+	for len(got) < len("hello") {
+		n, err := lnStream.Read(buf)
+		if err != nil {
+			t.Fatalf("Read error: %v", err)
+		}
+		got = append(got, buf[:n]...)
+	}
+	if string(got) != "hello" {
+		t.Fatalf("Read = %q; want %q", got, "hello")
+	}
+
+	// The listening handle replies to the association it last read from.
+	if _, err := lnStream.Write([]byte("HELLO")); err != nil {
+		t.Fatalf("Write(reply) error: %v", err)
+	}
+	cliStream.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf = make([]byte, 16)
+	n, err := cliStream.Read(buf)
+	if err != nil || string(buf[:n]) != "HELLO" {
+		t.Fatalf("Read(reply) = %q, %v; want %q", buf[:n], err, "HELLO")
+	}
+
+	lnStream.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	if _, err := lnStream.Read(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read error = %v; want %v", err, os.ErrDeadlineExceeded)
+	}
+
+	// Closing the listening handle resets the stream when the
+	// association negotiated RFC 6525, which the dialer sees as EOF.
+	if err := lnStream.Close(); err != nil {
+		t.Fatalf("Close error: %v", err)
+	}
+	if _, err := lnStream.Read(buf); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Read after Close error = %v; want %v", err, ErrClosed)
+	}
+	if f, err := cli.PeerFeatures(0); err == nil && f.StreamReconfig {
+		if _, err := cliStream.Read(buf); err != io.EOF {
+			t.Fatalf("Read after peer reset error = %v; want %v", err, io.EOF)
+		}
+	}
+
+	cli.Close()
+	cliStream.SetReadDeadline(time.Time{})
+	if _, err := cliStream.Read(buf); !errors.Is(err, ErrClosed) && err != io.EOF {
+		t.Fatalf("Read after SCTPConn.Close error = %v; want %v", err, ErrClosed)
+	}
+}
+
+func TestSCTPAcceptStream(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SubscribeEvents(SCTPEventMask{Association: true, StreamReset: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+
+	for _, id := range []uint16{2, 5} {
+		if _, err := cli.Stream(0, id, 9).Write([]byte{byte(id)}); err != nil {
+			t.Fatalf("Write on stream %d error: %v", id, err)
+		}
+	}
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var accepted []*SCTPStream
+	for _, id := range []uint16{2, 5} {
+		s, err := ln.AcceptStream()
+		if err != nil {
+			t.Fatalf("AcceptStream error: %v", err)
+		}
+		accepted = append(accepted, s)
+		if s.id != id || s.ppid != 9 || s.assocID == 0 {
+			t.Fatalf("accepted stream %d, PPID %d, association %d; want stream %d, PPID 9", s.id, s.ppid, s.assocID, id)
+		}
+		buf := make([]byte, 4)
+		n, err := s.Read(buf)
+		if err != nil || n != 1 || buf[0] != byte(id) {
+			t.Fatalf("Read = %v, %v; want [%d]", buf[:n], err, id)
+		}
+		if _, err := s.Write([]byte("ok")); err != nil {
+			t.Fatalf("Write(reply) error: %v", err)
+		}
+		if ra, ok := s.RemoteAddr().(*SCTPAddr); !ok || !ra.IP.Equal(IPv4(127, 0, 0, 1)) {
+			t.Fatalf("RemoteAddr = %v; want an address of 127.0.0.1", s.RemoteAddr())
+		}
+	}
+
+	// A further message on an accepted stream goes to its handle.
+	if _, err := cli.Stream(0, 2, 9).Write([]byte("again")); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	accepted[0].SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 16)
+	if n, err := accepted[0].Read(buf); err != nil || string(buf[:n]) != "again" {
+		t.Fatalf("Read = %q, %v; want %q", buf[:n], err, "again")
+	}
+
+	ln.Close()
+	if _, err := ln.AcceptStream(); !errors.Is(err, ErrClosed) {
+		t.Fatalf("AcceptStream after Close error = %v; want %v", err, ErrClosed)
+	}
+}
+
+func TestSCTPSendFrom(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	cli, err := DialSCTP("sctp4", nil, ln.LocalAddr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer cli.Close()
+	max, err := cli.MaxMessageSize(0)
+	if err != nil {
+		t.Fatalf("MaxMessageSize error: %v", err)
+	}
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 1<<14) // 256 KiB
+	sent := make(chan error, 1)
+	go func() {
+		n, err := cli.SendFrom(bytes.NewReader(data), &SCTPSndInfo{Stream: 1, PPID: 7})
+		if err == nil && n != int64(len(data)) {
+			err = fmt.Errorf("sent %d bytes; want %d", n, len(data))
+		}
+		sent <- err
+	}()
+
+	ln.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var got []byte
+	for m, err := range ln.Messages() {
+		if err != nil {
+			t.Fatalf("Messages error: %v", err)
+		}
+		if len(m.Data) > max || m.Info.Stream != 1 || m.Info.PPID != 7 {
+			t.Fatalf("message of %d bytes on stream %d, PPID %d; want at most %d bytes on stream 1, PPID 7", len(m.Data), m.Info.Stream, m.Info.PPID, max)
+		}
+		if got = append(got, m.Data...); len(got) >= len(data) {
+			break
+		}
+	}
+	if err := <-sent; err != nil {
+		t.Fatalf("SendFrom error: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("received %d bytes differing from the %d sent", len(got), len(data))
+	}
+
+	// The stream handles copy in both directions.
+	lnStream, cliStream := ln.Stream(0, 2, 7), cli.Stream(0, 2, 7)
+	if _, err := io.Copy(cliStream, bytes.NewReader(data[:1000])); err != nil {
+		t.Fatalf("io.Copy to SCTPStream error: %v", err)
+	}
+	lnStream.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1000)
+	if _, err := io.ReadFull(lnStream, buf); err != nil || !bytes.Equal(buf, data[:1000]) {
+		t.Fatalf("io.ReadFull from SCTPStream = %v", err)
+	}
+}
+
+func TestSCTPListenerPeelOff(t *testing.T) {
+	requireSCTP(t)
+
+	lc, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer lc.Close()
+	ln, err := NewSCTPListener(lc)
+	if err != nil {
+		t.Fatalf("NewSCTPListener error: %v", err)
+	}
+
+	dc, err := DialSCTP("sctp4", nil, ln.Addr().(*SCTPAddr))
+	if err != nil {
+		t.Fatalf("DialSCTP error: %v", err)
+	}
+	defer dc.Close()
+	cli, err := dc.PeelOff(0)
+	if err != nil {
+		t.Fatalf("PeelOff error: %v", err)
+	}
+	defer cli.Close()
+
+	if err := ln.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatalf("SetDeadline error: %v", err)
+	}
+	srv, err := ln.AcceptSCTP()
+	if err != nil {
+		t.Fatalf("AcceptSCTP error: %v", err)
+	}
+	defer srv.Close()
+
+	if _, err := cli.Write([]byte("ping")); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	srv.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 16)
+	n, err := srv.Read(buf)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if string(buf[:n]) != "ping" {
+		t.Fatalf("Read = %q; want %q", buf[:n], "ping")
+	}
+
+	if err := cli.CloseWrite(); err != nil {
+		t.Fatalf("CloseWrite error: %v", err)
+	}
+	if n, err := srv.Read(buf); n != 0 || err == nil {
+		t.Fatalf("Read after CloseWrite = %d, %v; want EOF", n, err)
+	}
+}
+
+func TestSCTPDialedReadFilter(t *testing.T) {
+	peer := &SCTPAddr{IP: IPv4(192, 0, 2, 1), Port: 3868}
+	other := &SCTPAddr{IP: IPv4(192, 0, 2, 9), Port: 3868}
+	c := &SCTPConn{conn: conn{fd: &netFD{raddr: peer}}}
+	if !c.dialed() {
+		t.Fatal("dialed() = false for an unconnected conn with a remote address")
+	}
+
+	if c.ownMessage(other, &SCTPRcvInfo{AssocID: 3}) {
+		t.Error("message from another peer accepted")
+	}
+	if !c.ownMessage(peer, &SCTPRcvInfo{AssocID: 7}) || c.dialAssoc != 7 {
+		t.Fatalf("message from the dialed peer rejected or assoc not learned (dialAssoc=%d)", c.dialAssoc)
+	}
+	if c.ownMessage(peer, &SCTPRcvInfo{AssocID: 8}) {
+		t.Error("message of another association from the dialed address accepted")
+	}
+
+	ac := sctpAssocChange{
+		sctpNotificationHeader: sctpNotificationHeader{Type: sctpEventAssociation, Length: uint32(sizeofSCTPAssocChange)},
+		State:                  uint16(SCTPCommLost),
+		AssocID:                8,
+	}
+	b := unsafe.Slice((*byte)(unsafe.Pointer(&ac)), sizeofSCTPAssocChange)
+	if c.dialEnded(b) {
+		t.Error("end of another association ends the dialed one")
+	}
+	ac.AssocID, ac.State = 7, uint16(SCTPCommUp)
+	if c.dialEnded(b) {
+		t.Error("SCTP_COMM_UP ends the dialed association")
+	}
+	ac.State = uint16(SCTPShutdownComplete)
+
+	// A notification read in parts is reassembled before it is
+	// decoded.
+	if nt := c.dialNotification(b[:3], SCTPMsgNotification); nt != nil {
+		t.Errorf("part of a notification returned as complete: %x", nt)
+	}
+	if nt := c.dialNotification(b[3:], SCTPMsgNotification|SCTPMsgEOR); !bytes.Equal(nt, b) {
+		t.Fatalf("reassembled notification = %x; want %x", nt, b)
+	}
+	if !c.dialEnded(b) || !c.readEOF {
+		t.Error("SCTP_SHUTDOWN_COMP does not end the dialed association")
+	}
+	if n, err := c.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Errorf("Read after the association ended = %d, %v; want 0, EOF", n, err)
+	}
+}
+
+func TestSCTPDialedReadWrite(t *testing.T) {
+	requireSCTP(t)
+
+	ln, err := ListenSCTP("sctp4", &SCTPAddr{IP: IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		t.Fatalf("ListenSCTP error: %v", err)
+	}
+	defer ln.Close()
+	if err := ln.SubscribeEvents(SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+	c, err := Dial("sctp4", ln.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Dial error: %v", err)
+	}
+	defer c.Close()
+	if err := c.(*SCTPConn).SubscribeEvents(SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents error: %v", err)
+	}
+
+	if _, err := io.Copy(c, strings.NewReader("ping")); err != nil {
+		t.Fatalf("io.Copy to dialed conn error: %v", err)
+	}
+	ln.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var m *SCTPMessage
+	for m, err = range ln.Messages() {
+		break
+	}
+	if err != nil || string(m.Data) != "ping" {
+		t.Fatalf("received %v, %v; want ping", m, err)
+	}
+
+	// Read skips the SCTP_COMM_UP notification and sees only the reply.
+	if _, err := ln.WriteToSCTP([]byte("pong\n"), m.Addr, &SCTPSndInfo{AssocID: m.Info.AssocID}); err != nil {
+		t.Fatalf("WriteToSCTP error: %v", err)
+	}
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil || line != "pong\n" {
+		t.Fatalf("ReadString = %q, %v; want pong", line, err)
+	}
+
+	// Ending the association from the listener ends the stream.
+	if err := ln.AbortAssoc(m.Info.AssocID); err != nil {
+		t.Fatalf("AbortAssoc error: %v", err)
+	}
+	// The buffer is smaller than the notification.
+	if n, err := c.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Fatalf("Read after abort = %d, %v; want 0, EOF", n, err)
+	}
+}
@@ -8,7 +8,13 @@ package net
 
 import (
 	"context"
+	"errors"
+	"internal/poll"
+	"os"
+	"runtime"
 	"syscall"
+	"time"
+	"unsafe"
 )
 
 func sockaddrToSCTP(sa syscall.Sockaddr) Addr {
@@ -59,7 +65,7 @@ func (c *SCTPConn) readFromSCTP(b []byte) (n int, oobn int, flags int, addr *SCT
 	return
 }
 
-func (c *SCTPConn) writeToSCTP(b []byte, addr *SCTPAddr, info *SCTPSndInfo) (int, error) {
+func (c *SCTPConn) writeToSCTP(b []byte, addr *SCTPAddr, info *SCTPSndInfo, deadline time.Time) (int, error) {
 	if c.fd.isConnected && addr != nil {
 		return 0, ErrWriteToConnected
 	}
@@ -82,13 +88,82 @@ func (c *SCTPConn) writeToSCTP(b []byte, addr *SCTPAddr, info *SCTPSndInfo) (int
 			return 0, err
 		}
 	}
-	n, _, err := c.fd.writeMsg(b, oob, sa)
+	var n int
+	if deadline.IsZero() {
+		n, _, err = c.fd.writeMsg(b, oob, sa)
+	} else {
+		n, err = c.sendMsgBefore(b, oob, sa, deadline)
+	}
 	if err != nil {
+		if errors.Is(err, syscall.EMSGSIZE) {
+			assocID := c.assocID
+			if info != nil && info.AssocID != 0 {
+				assocID = info.AssocID
+			}
+			if limit, lerr := maxMessageSizeSCTP(c.fd, assocID); lerr == nil {
+				return 0, &SCTPMessageSizeError{Len: len(b), Max: limit}
+			}
+		}
 		return 0, err
 	}
 	return n, nil
 }
 
+// sendMsgBefore sends a message, waiting in the runtime poller while
+// the send buffer is full until deadline, or until the write deadline
+// of c if that is earlier, and then returns os.ErrDeadlineExceeded
+// without sending the message.
+//
+// The socket has a single write deadline, which the poller applies.
+// Writes on the socket are serialized, so deadline is set on it only
+// while this write holds it and waits, and the write deadline of c is
+// restored before any other write can wait. Setting the write deadline
+// of c meanwhile keeps deadline if it is earlier.
+func (c *SCTPConn) sendMsgBefore(b, oob []byte, sa syscall.Sockaddr, deadline time.Time) (int, error) {
+	fd := c.fd
+	var (
+		n    int
+		serr error
+	)
+	err := fd.pfd.RawWrite(func(s uintptr) bool {
+		for {
+			n, serr = syscall.SendmsgN(int(s), b, oob, sa, syscall.MSG_DONTWAIT)
+			if serr == syscall.EINTR {
+				continue
+			}
+			if serr != syscall.EAGAIN {
+				return true
+			}
+			c.mu.Lock()
+			c.sendDeadline = deadline
+			c.keepSendDeadline()
+			c.mu.Unlock()
+			serr = fd.pfd.WaitWrite()
+			c.mu.Lock()
+			c.sendDeadline = time.Time{}
+			fd.pfd.SetWriteDeadline(c.wdeadline)
+			c.mu.Unlock()
+			if serr != nil {
+				return true
+			}
+			// The write deadline of c may have been moved while
+			// waiting.
+			if !time.Now().Before(deadline) {
+				serr = os.ErrDeadlineExceeded
+				return true
+			}
+		}
+	})
+	runtime.KeepAlive(fd)
+	if err != nil {
+		return 0, err
+	}
+	if serr != nil {
+		return 0, wrapSyscallError("sendmsg", serr)
+	}
+	return n, nil
+}
+
 func (sd *sysDialer) dialSCTP(ctx context.Context, laddr, raddr *SCTPAddr) (*SCTPConn, error) {
 	ctrlCtxFn := sd.Dialer.ControlContext
 	if ctrlCtxFn == nil && sd.Dialer.Control != nil {
@@ -102,6 +177,7 @@ func (sd *sysDialer) dialSCTP(ctx context.Context, laddr, raddr *SCTPAddr) (*SCT
 	if laddr != nil {
 		la = laddr
 	}
+	ctrlCtxFn = sctpSocketControl(ctrlCtxFn, sd.Dialer.sctpFeatures)
 	fd, err := internetSocket(ctx, sd.network, la, nil, syscall.SOCK_SEQPACKET, syscall.IPPROTO_SCTP, "dial", ctrlCtxFn)
 	if err != nil {
 		return nil, err
@@ -117,9 +193,61 @@ func (sl *sysListener) listenSCTP(ctx context.Context, laddr *SCTPAddr) (*SCTPCo
 			return sl.ListenConfig.Control(network, address, c)
 		}
 	}
+	ctrlCtxFn = sctpSocketControl(ctrlCtxFn, sl.ListenConfig.sctpFeatures)
 	fd, err := internetSocket(ctx, sl.network, laddr, nil, syscall.SOCK_SEQPACKET, syscall.IPPROTO_SCTP, "listen", ctrlCtxFn)
 	if err != nil {
 		return nil, err
 	}
 	return newSCTPConn(fd), nil
 }
+
+// sctpSocketControl returns the control function for a new SCTP
+// socket: ctrlCtxFn, followed by setting the protocol extensions f if
+// not nil. It runs before the socket is bound, so f applies to every
+// association of the socket.
+func sctpSocketControl(ctrlCtxFn func(context.Context, string, string, syscall.RawConn) error, f *SCTPFeatures) func(context.Context, string, string, syscall.RawConn) error {
+	if f == nil {
+		return ctrlCtxFn
+	}
+	return func(ctx context.Context, network, address string, c syscall.RawConn) error {
+		if ctrlCtxFn != nil {
+			if err := ctrlCtxFn(ctx, network, address, c); err != nil {
+				return err
+			}
+		}
+		return setFeaturesSCTP(c.(*rawConn).fd, *f)
+	}
+}
+
+func peelOffSCTP(fd *netFD, assocID int32) (*netFD, error) {
+	arg := sctpPeeloffArg{AssocID: assocID}
+	// See ../syscall/exec_unix.go for description of ForkLock.
+	syscall.ForkLock.RLock()
+	_, err := getSockoptBytes(fd, syscall.IPPROTO_SCTP, sctpSockoptPeeloff, unsafe.Slice((*byte)(unsafe.Pointer(&arg)), sizeofSCTPPeeloffArg))
+	if err == nil {
+		syscall.CloseOnExec(int(arg.SD))
+	}
+	syscall.ForkLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	s := int(arg.SD)
+	if err := syscall.SetNonblock(s, true); err != nil {
+		poll.CloseFunc(s)
+		return nil, os.NewSyscallError("setnonblock", err)
+	}
+	nfd, err := newFD(s, fd.family, fd.sotype, fd.net)
+	if err != nil {
+		poll.CloseFunc(s)
+		return nil, err
+	}
+	if err := nfd.init(); err != nil {
+		nfd.Close()
+		return nil, err
+	}
+	nfd.isConnected = true
+	lsa, _ := syscall.Getsockname(s)
+	rsa, _ := syscall.Getpeername(s)
+	nfd.setAddr(nfd.addrFunc()(lsa), nfd.addrFunc()(rsa))
+	return nfd, nil
+}
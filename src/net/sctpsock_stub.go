@@ -10,10 +10,29 @@ import (
 	"context"
 	"errors"
 	"syscall"
+	"time"
 )
 
 var errSCTPUnsupported = errors.New("sctp is not supported on this platform")
 
+var errSCTPMessageSize error = syscall.EMSGSIZE
+
+// sctpMsgEOR is the value of SCTPMsgEOR. No read sets it here.
+const sctpMsgEOR = 0x80
+
+// connectedSCTP reports whether fd is a connected socket, such as a
+// peeled-off association.
+func connectedSCTP(fd *netFD) bool { return fd.isConnected }
+
+// sctpErrno returns the system error number wrapped by err, or 0.
+func sctpErrno(err error) uintptr {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return uintptr(errno)
+	}
+	return 0
+}
+
 func sockaddrToSCTP(syscall.Sockaddr) Addr { return nil }
 
 func (a *SCTPAddr) family() int {
@@ -41,7 +60,7 @@ func (c *SCTPConn) readFromSCTP([]byte) (n int, oobn int, flags int, addr *SCTPA
 	return 0, 0, 0, nil, nil, errSCTPUnsupported
 }
 
-func (c *SCTPConn) writeToSCTP([]byte, *SCTPAddr, *SCTPSndInfo) (int, error) {
+func (c *SCTPConn) writeToSCTP([]byte, *SCTPAddr, *SCTPSndInfo, time.Time) (int, error) {
 	return 0, errSCTPUnsupported
 }
 
@@ -72,3 +91,66 @@ func connectAddrsSCTP(*netFD, []SCTPAddr) (int32, error) { return 0, errSCTPUnsu
 func localAddrsSCTP(*netFD, int32) ([]SCTPAddr, error) { return nil, errSCTPUnsupported }
 
 func peerAddrsSCTP(*netFD, int32) ([]SCTPAddr, error) { return nil, errSCTPUnsupported }
+
+func assocStatsSCTP(*netFD, int32) (SCTPAssocStats, error) {
+	return SCTPAssocStats{}, errSCTPUnsupported
+}
+
+func setRemoteUDPEncapsPortSCTP(*netFD, int32, *SCTPAddr, int) error { return errSCTPUnsupported }
+
+func remoteUDPEncapsPortSCTP(*netFD, int32, *SCTPAddr) (int, error) { return 0, errSCTPUnsupported }
+
+func sctpUDPEncapsPort() (int, error) { return 0, errSCTPUnsupported }
+
+func setMaxSegSCTP(*netFD, int32, int) error { return errSCTPUnsupported }
+
+func maxSegSCTP(*netFD, int32) (int, error) { return 0, errSCTPUnsupported }
+
+func setDisableFragmentsSCTP(*netFD, bool) error { return errSCTPUnsupported }
+
+func disableFragmentsSCTP(*netFD) (bool, error) { return false, errSCTPUnsupported }
+
+func setPLPMTUDProbeIntervalSCTP(*netFD, int32, *SCTPAddr, time.Duration) error {
+	return errSCTPUnsupported
+}
+
+func plpmtudProbeIntervalSCTP(*netFD, int32, *SCTPAddr) (time.Duration, error) {
+	return 0, errSCTPUnsupported
+}
+
+func maxMessageSizeSCTP(*netFD, int32) (int, error) { return 0, errSCTPUnsupported }
+
+func setFeaturesSCTP(*netFD, SCTPFeatures) error { return errSCTPUnsupported }
+
+func featuresSCTP(*netFD, int32) (SCTPFeatures, error) { return SCTPFeatures{}, errSCTPUnsupported }
+
+func parseSCTPNotification([]byte) (SCTPNotification, error) { return nil, errSCTPUnsupported }
+
+func peerPathsSCTP(*netFD, int32) ([]SCTPPath, error) { return nil, errSCTPUnsupported }
+
+func setAdaptationLayerSCTP(*netFD, uint32) error { return errSCTPUnsupported }
+
+func adaptationLayerSCTP(*netFD) (uint32, error) { return 0, errSCTPUnsupported }
+
+func setAssocContextSCTP(*netFD, int32, uint32) error { return errSCTPUnsupported }
+func assocContextSCTP(*netFD, int32) (uint32, error)  { return 0, errSCTPUnsupported }
+
+func enableStreamResetSCTP(*netFD, int32) error      { return errSCTPUnsupported }
+func resetStreamsSCTP(*netFD, int32, []uint16) error { return errSCTPUnsupported }
+func sctpResetBusy(error) bool                       { return false }
+func sctpResetUnsupported(error) bool                { return false }
+
+func senderDrySCTP(*netFD) (bool, error) { return false, errSCTPUnsupported }
+
+func sendSCTPControl(*netFD, *SCTPAddr, *SCTPSndInfo) error { return errSCTPUnsupported }
+
+func setRecvRcvInfoSCTP(*netFD, bool) error { return errSCTPUnsupported }
+func setMappedV4SCTP(*netFD, bool) error    { return errSCTPUnsupported }
+
+func setAutoCloseSCTP(*netFD, int) error { return errSCTPUnsupported }
+
+func autoCloseSCTP(*netFD) (int, error) { return 0, errSCTPUnsupported }
+
+func peelOffSCTP(*netFD, int32) (*netFD, error) { return nil, errSCTPUnsupported }
+
+func assocIDsSCTP(*netFD) ([]int32, error) { return nil, errSCTPUnsupported }
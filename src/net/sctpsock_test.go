@@ -6,8 +6,18 @@ package net
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+	"unsafe"
 )
 
 func TestResolveSCTPAddrUnknownNetwork(t *testing.T) {
@@ -37,3 +47,257 @@ func TestSCTPAddrString(t *testing.T) {
 		t.Fatalf("SCTPAddr.String() = %q; want %q", got, want)
 	}
 }
+
+func TestSCTPStats(t *testing.T) {
+	c := &SCTPConn{conn: conn{fd: &netFD{}}}
+	c.countWrite(5, &SCTPSndInfo{Stream: 2, PPID: 46}, nil)
+	c.countWrite(7, &SCTPSndInfo{Stream: 2, PPID: 46}, nil)
+	c.countWrite(3, nil, nil)
+	c.countWrite(0, nil, &OpError{Op: "write", Err: &SCTPMessageSizeError{Len: 1 << 20, Max: 1 << 16}})
+	c.countWrite(0, nil, errors.New("no errno"))
+
+	buf := make([]byte, 16)
+	c.countRead(buf, 16, 0, &SCTPRcvInfo{Stream: 1, PPID: 51})
+	c.countRead(buf, 4, SCTPMsgEOR, &SCTPRcvInfo{Stream: 1, PPID: 51})
+	typ := uint16(0x8001)
+	copy(buf, unsafe.Slice((*byte)(unsafe.Pointer(&typ)), 2))
+	c.countRead(buf, 16, SCTPMsgNotification|SCTPMsgEOR, nil)
+	// A notification read in parts is counted once, by the type at
+	// its start, even when a part starts with other bytes.
+	c.countRead(buf, 1, SCTPMsgNotification, nil)
+	c.countRead(buf[1:], 3, SCTPMsgNotification, nil)
+	c.countRead([]byte{0x05, 0x80}, 2, SCTPMsgNotification|SCTPMsgEOR, nil)
+
+	s := c.Stats()
+	want := []SCTPStreamStats{
+		{Stream: 0, PPID: 0, MessagesOut: 1, BytesOut: 3},
+		{Stream: 1, PPID: 51, MessagesIn: 1, BytesIn: 20},
+		{Stream: 2, PPID: 46, MessagesOut: 2, BytesOut: 12},
+	}
+	if fmt.Sprint(s.Streams) != fmt.Sprint(want) {
+		t.Errorf("Streams = %+v; want %+v", s.Streams, want)
+	}
+	if s.Notifications[0x8001] != 2 || len(s.Notifications) != 1 {
+		t.Errorf("Notifications = %v; want 2 of type 0x8001", s.Notifications)
+	}
+	wantErrs := map[uintptr]uint64{0: 1}
+	wantErrs[sctpErrno(errSCTPMessageSize)]++
+	if !maps.Equal(s.SendErrors, wantErrs) {
+		t.Errorf("SendErrors = %v; want %v", s.SendErrors, wantErrs)
+	}
+	if s.TruncatedReads != 1 {
+		t.Errorf("TruncatedReads = %d; want 1", s.TruncatedReads)
+	}
+
+	// The snapshot is independent of later traffic and marshals to
+	// JSON for expvar.
+	c.countWrite(1, nil, nil)
+	if s.Streams[0].MessagesOut != 1 {
+		t.Error("Stats snapshot changed after a write")
+	}
+	if _, err := json.Marshal(s); err != nil {
+		t.Errorf("json.Marshal(Stats()): %v", err)
+	}
+}
+
+func TestSCTPReassembler(t *testing.T) {
+	var live []int32
+	r := &sctpReassembler{
+		max:     8,
+		assocs:  func() ([]int32, error) { return live, nil },
+		partial: make(map[sctpStreamKey][]byte),
+	}
+	add := func(assocID int32, stream uint16, s string, flags int) (string, error) {
+		t.Helper()
+		b, err := r.add(sctpStreamKey{assocID, stream}, []byte(s), flags)
+		return string(b), err
+	}
+
+	if m, err := add(1, 0, "abc", 0); m != "" || err != nil {
+		t.Fatalf("partial read = %q, %v; want nothing", m, err)
+	}
+	if m, err := add(1, 1, "xyz", SCTPMsgEOR); m != "xyz" || err != nil {
+		t.Fatalf("whole message on another stream = %q, %v; want %q", m, err, "xyz")
+	}
+	if m, err := add(1, 0, "def", SCTPMsgEOR); m != "abcdef" || err != nil {
+		t.Fatalf("end of message = %q, %v; want %q", m, err, "abcdef")
+	}
+
+	// A message past the size limit drops its association, including
+	// the end of the message.
+	add(2, 0, "abcde", 0)
+	if _, err := add(2, 0, "fghij", 0); err != errSCTPMessageTooLarge {
+		t.Fatalf("oversized message: %v; want errSCTPMessageTooLarge", err)
+	}
+	if m, err := add(2, 0, "k", SCTPMsgEOR); m != "" || err != nil {
+		t.Fatalf("end of dropped message = %q, %v; want nothing", m, err)
+	}
+	if m, _ := add(2, 1, "new", SCTPMsgEOR); m != "" {
+		t.Fatalf("message of a dropped association = %q; want nothing", m)
+	}
+
+	// The number of partial messages is bounded. Room is made by
+	// discarding those of associations that no longer exist.
+	for i := range sctpMaxPartial {
+		live = append(live, int32(10+i))
+		add(int32(10+i), 0, "a", 0)
+	}
+	if _, err := add(100, 0, "a", 0); err != errSCTPMessageTooLarge {
+		t.Fatalf("partial message past the limit: %v; want errSCTPMessageTooLarge", err)
+	}
+	live = []int32{10}
+	if _, err := add(101, 0, "a", 0); err != nil {
+		t.Fatalf("partial message after pruning: %v", err)
+	}
+	if len(r.partial) != 2 {
+		t.Errorf("%d partial messages after pruning; want 2", len(r.partial))
+	}
+}
+
+func TestSCTPMessagesInvalidConn(t *testing.T) {
+	c := &SCTPConn{}
+	for m, err := range c.Messages() {
+		if m != nil || err != syscall.EINVAL {
+			t.Fatalf("Messages on invalid conn yielded %v, %v; want nil, EINVAL", m, err)
+		}
+	}
+	if _, ok := <-c.Notifications(); ok {
+		t.Fatal("Notifications on invalid conn returned an open channel")
+	}
+}
+
+func TestSCTPStreamDemux(t *testing.T) {
+	c := &SCTPConn{conn: conn{fd: &netFD{}}}
+	all := &SCTPStream{c: c, id: 3, changed: make(chan struct{})}
+	s := &SCTPStream{c: c, assocID: 7, id: 3, changed: make(chan struct{})}
+	c.streams = map[sctpStreamKey]*SCTPStream{{0, 3}: all, {7, 3}: s}
+	if got := c.streamFor(7, 3); got != s {
+		t.Fatalf("streamFor(7, 3) = %p; want the assoc 7 handle %p", got, s)
+	}
+	if got := c.streamFor(8, 3); got != all {
+		t.Fatalf("streamFor(8, 3) = %p; want the assoc 0 handle %p", got, all)
+	}
+	if got := c.streamFor(7, 4); got != nil {
+		t.Fatalf("streamFor(7, 4) = %p; want nil", got)
+	}
+
+	done := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(s)
+		done <- b
+	}()
+	s.deliver([]byte("hello, "), nil, 7, nil)
+	s.deliver([]byte("world"), nil, 7, nil)
+	c.observeStreams(&SCTPStreamResetEvent{Flags: SCTPStreamResetIncoming, AssocID: 7, Streams: []uint16{4}})
+	c.observeStreams(&SCTPStreamResetEvent{Flags: SCTPStreamResetIncoming, AssocID: 7, Streams: []uint16{3}})
+	if got := string(<-done); got != "hello, world" {
+		t.Fatalf("ReadAll = %q; want %q", got, "hello, world")
+	}
+	if all.eof {
+		t.Fatal("assoc 0 handle of a listening socket ended with one association")
+	}
+
+	s = &SCTPStream{c: c, id: 5, changed: make(chan struct{})}
+	s.SetReadDeadline(time.Now().Add(10 * time.Millisecond))
+	if _, err := s.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read after deadline error = %v; want %v", err, os.ErrDeadlineExceeded)
+	}
+	s.SetReadDeadline(time.Time{})
+	s.stop(io.ErrUnexpectedEOF)
+	if _, err := s.Read(make([]byte, 1)); err != io.ErrUnexpectedEOF {
+		t.Fatalf("Read after reader stopped error = %v; want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestSCTPStreamQueueBound(t *testing.T) {
+	c := &SCTPConn{conn: conn{fd: &netFD{}}}
+	s := &SCTPStream{c: c, assocID: 7, id: 1, changed: make(chan struct{})}
+	for range sctpStreamQueueLen {
+		if !s.deliver([]byte("x"), nil, 7, nil) {
+			t.Fatal("deliver below the bound reported stop")
+		}
+	}
+
+	// A full handle holds the reader back until it is read.
+	delivered := make(chan bool)
+	go func() { delivered <- s.deliver([]byte("y"), nil, 7, nil) }()
+	select {
+	case <-delivered:
+		t.Fatal("deliver to a full handle did not wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if _, err := s.Read(make([]byte, 1)); err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if !<-delivered {
+		t.Fatal("deliver after Read reported stop")
+	}
+	if len(s.queue) != sctpStreamQueueLen {
+		t.Fatalf("queue holds %d messages; want %d", len(s.queue), sctpStreamQueueLen)
+	}
+
+	// Stopping the reader releases a waiting delivery.
+	stop := make(chan struct{})
+	go func() { delivered <- s.deliver([]byte("z"), nil, 7, stop) }()
+	close(stop)
+	if <-delivered {
+		t.Fatal("deliver after stop reported success")
+	}
+}
+
+func TestSCTPSendChunks(t *testing.T) {
+	var sizes []int
+	var got []byte
+	write := func(b []byte) (int, error) {
+		if len(b) > 3 && len(sizes) == 2 {
+			// The limit shrank, as when the path MTU drops with
+			// fragmentation disabled.
+			return 0, &OpError{Op: "write", Err: &SCTPMessageSizeError{Len: len(b), Max: 3}}
+		}
+		sizes = append(sizes, len(b))
+		got = append(got, b...)
+		return len(b), nil
+	}
+	const data = "0123456789abcdefghij"
+	n, err := sendChunks(strings.NewReader(data), 8, write)
+	if err != nil || n != int64(len(data)) || string(got) != data {
+		t.Fatalf("sendChunks = %d, %v, sent %q; want %d, nil, %q", n, err, got, len(data), data)
+	}
+	if want := []int{8, 8, 3, 1}; !slices.Equal(sizes, want) {
+		t.Errorf("message sizes = %v; want %v", sizes, want)
+	}
+
+	werr := errors.New("write failed")
+	n, err = sendChunks(strings.NewReader(data), 8, func(b []byte) (int, error) { return 0, werr })
+	if n != 0 || err != werr {
+		t.Errorf("sendChunks with failing write = %d, %v; want 0, %v", n, err, werr)
+	}
+}
+
+type sctpWriteRecorder [][]byte
+
+func (w *sctpWriteRecorder) Write(b []byte) (int, error) {
+	*w = append(*w, slices.Clone(b))
+	return len(b), nil
+}
+
+func TestSCTPStreamWriteTo(t *testing.T) {
+	c := &SCTPConn{conn: conn{fd: &netFD{}}}
+	s := &SCTPStream{c: c, assocID: 7, id: 1, changed: make(chan struct{})}
+	s.deliver([]byte("first"), nil, 7, nil)
+	s.deliver([]byte("second"), nil, 7, nil)
+	s.end(7)
+
+	// A partial read leaves the rest of the message for WriteTo.
+	if n, err := s.Read(make([]byte, 2)); n != 2 || err != nil {
+		t.Fatalf("Read = %d, %v; want 2, nil", n, err)
+	}
+	var w sctpWriteRecorder
+	n, err := s.WriteTo(&w)
+	if err != nil || n != int64(len("rstsecond")) {
+		t.Fatalf("WriteTo = %d, %v; want %d, nil", n, err, len("rstsecond"))
+	}
+	if got := fmt.Sprintf("%q", w); got != `["rst" "second"]` {
+		t.Errorf("WriteTo wrote %s; want one write per message", got)
+	}
+}
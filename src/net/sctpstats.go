@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"slices"
+	"unsafe"
+)
+
+// SCTPStats holds the userland counters of an [SCTPConn], as returned
+// by [SCTPConn.Stats].
+//
+// The counters cover the messages read by [SCTPConn.ReadFromSCTP] and
+// written by [SCTPConn.WriteToSCTP], and the methods built on them.
+// They are kept by the package and need no kernel support. A snapshot
+// marshals to JSON; package net/sctpvar publishes the counters of
+// connections through expvar.
+type SCTPStats struct {
+	// Streams holds the traffic of each stream and payload
+	// protocol identifier, ordered by stream and then PPID.
+	// Received messages are only attributed to their stream and
+	// PPID when SCTP_RECVRCVINFO is enabled (see
+	// [SCTPConn.SetRecvRcvInfo]); otherwise they are counted under
+	// stream 0 and PPID 0.
+	Streams []SCTPStreamStats
+
+	// Notifications counts the event notifications read, by
+	// notification type (for example 0x8001 for SCTP_ASSOC_CHANGE
+	// on Linux).
+	Notifications map[uint16]uint64
+
+	// SendErrors counts failed writes by system error number, the
+	// value of the syscall.Errno wrapped by the error. Writes that
+	// failed without a system error are counted under 0.
+	SendErrors map[uintptr]uint64
+
+	// TruncatedReads counts reads that returned only part of a
+	// message because the buffer was too small. The rest of the
+	// message is returned by the following reads.
+	TruncatedReads uint64
+}
+
+// SCTPStreamStats is the traffic of one stream and payload protocol
+// identifier of an [SCTPConn].
+type SCTPStreamStats struct {
+	Stream      uint16
+	PPID        uint32
+	MessagesIn  uint64
+	BytesIn     uint64
+	MessagesOut uint64
+	BytesOut    uint64
+}
+
+type sctpStreamPPID struct {
+	stream uint16
+	ppid   uint32
+}
+
+// sctpCounters is the mutable form of SCTPStats.
+type sctpCounters struct {
+	streams        map[sctpStreamPPID]*SCTPStreamStats
+	notifications  map[uint16]uint64
+	sendErrors     map[uintptr]uint64
+	truncatedReads uint64
+
+	// notifyHead holds the first bytes of the notification being
+	// read, which carry its type, until its last read.
+	notifyHead    [2]byte
+	notifyHeadLen int
+}
+
+func (s *sctpCounters) stream(stream uint16, ppid uint32) *SCTPStreamStats {
+	if s.streams == nil {
+		s.streams = make(map[sctpStreamPPID]*SCTPStreamStats)
+	}
+	k := sctpStreamPPID{stream, ppid}
+	st := s.streams[k]
+	if st == nil {
+		st = &SCTPStreamStats{Stream: stream, PPID: ppid}
+		s.streams[k] = st
+	}
+	return st
+}
+
+// Stats returns a snapshot of the counters of c.
+func (c *SCTPConn) Stats() SCTPStats {
+	if !c.ok() {
+		return SCTPStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := SCTPStats{
+		Streams:        make([]SCTPStreamStats, 0, len(c.stats.streams)),
+		Notifications:  make(map[uint16]uint64, len(c.stats.notifications)),
+		SendErrors:     make(map[uintptr]uint64, len(c.stats.sendErrors)),
+		TruncatedReads: c.stats.truncatedReads,
+	}
+	for _, st := range c.stats.streams {
+		s.Streams = append(s.Streams, *st)
+	}
+	slices.SortFunc(s.Streams, func(a, b SCTPStreamStats) int {
+		if a.Stream != b.Stream {
+			return int(a.Stream) - int(b.Stream)
+		}
+		switch {
+		case a.PPID < b.PPID:
+			return -1
+		case a.PPID > b.PPID:
+			return 1
+		}
+		return 0
+	})
+	for k, v := range c.stats.notifications {
+		s.Notifications[k] = v
+	}
+	for k, v := range c.stats.sendErrors {
+		s.SendErrors[k] = v
+	}
+	return s
+}
+
+// countRead records a successful read of n bytes. c.mu must be held.
+//
+// A notification larger than b is read in parts, only the last of
+// which has SCTPMsgEOR set. It is counted once, on that read, under
+// the type found at the start of its first read.
+func (c *SCTPConn) countRead(b []byte, n, flags int, info *SCTPRcvInfo) {
+	if flags&SCTPMsgNotification != 0 {
+		s := &c.stats
+		s.notifyHeadLen += copy(s.notifyHead[s.notifyHeadLen:], b[:n])
+		if flags&SCTPMsgEOR == 0 {
+			return
+		}
+		if s.notifyHeadLen == len(s.notifyHead) {
+			var typ uint16
+			copy(unsafe.Slice((*byte)(unsafe.Pointer(&typ)), 2), s.notifyHead[:])
+			if s.notifications == nil {
+				s.notifications = make(map[uint16]uint64)
+			}
+			s.notifications[typ]++
+		}
+		s.notifyHeadLen = 0
+		return
+	}
+	var st *SCTPStreamStats
+	if info != nil {
+		st = c.stats.stream(info.Stream, info.PPID)
+	} else {
+		st = c.stats.stream(0, 0)
+	}
+	st.BytesIn += uint64(n)
+	if flags&SCTPMsgEOR != 0 {
+		st.MessagesIn++
+	} else {
+		c.stats.truncatedReads++
+	}
+}
+
+// countWrite records the outcome of a write. c.mu must be held.
+func (c *SCTPConn) countWrite(n int, info *SCTPSndInfo, err error) {
+	if err != nil {
+		if c.stats.sendErrors == nil {
+			c.stats.sendErrors = make(map[uintptr]uint64)
+		}
+		c.stats.sendErrors[sctpErrno(err)]++
+		return
+	}
+	var st *SCTPStreamStats
+	if info != nil {
+		st = c.stats.stream(info.Stream, info.PPID)
+	} else {
+		st = c.stats.stream(0, 0)
+	}
+	st.MessagesOut++
+	st.BytesOut += uint64(n)
+}
@@ -0,0 +1,525 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SCTPStream is a handle on one stream of an SCTP association,
+// returned by [SCTPConn.Stream] and [SCTPConn.AcceptStream]. It
+// implements [Conn], so code written for byte streams can use one
+// SCTP stream per logical channel.
+type SCTPStream struct {
+	c       *SCTPConn
+	assocID int32
+	id      uint16
+	ppid    uint32
+
+	mu        sync.Mutex
+	changed   chan struct{} // closed and replaced when the state below changes
+	queue     [][]byte      // received messages not yet read
+	lastAssoc int32         // association of the last message received
+	lastAddr  *SCTPAddr     // source address of the last message received
+	eof       bool          // the peer reset the stream or the association ended
+	err       error         // the reader of c stopped
+	closed    bool
+	rdeadline time.Time
+	wdeadline time.Time
+}
+
+// Stream returns a handle on stream id of the association identified
+// by assocID. Writes on the handle are sent on that stream with the
+// given payload protocol identifier, and reads return the data of the
+// messages received on the stream.
+//
+// An assocID of 0 selects the only association of a dialed or
+// peeled-off connection. On a listening socket, a handle with assocID
+// 0 receives the stream of every association that has no handle of
+// its own, and writes go to the association of the last message
+// received.
+//
+// Received messages are demultiplexed by the reader started by
+// [SCTPConn.Messages], which Stream starts if needed, and buffered
+// until read, up to 64 messages per handle. When a handle is full the
+// reader waits for it to be read, which holds back the messages of
+// every stream of c and so applies back-pressure to the peer.
+// Messages on streams without a handle are delivered by
+// Messages, open a handle returned by [SCTPConn.AcceptStream] once
+// AcceptStream has been called, or are discarded if neither was
+// called. If a handle on the stream is already open, Stream returns it.
+//
+// Reads return [io.EOF] once the peer has reset the stream or the
+// association has ended, which is only seen when
+// [SCTPEventMask.StreamReset] and [SCTPEventMask.Association] are
+// subscribed.
+func (c *SCTPConn) Stream(assocID int32, id uint16, ppid uint32) *SCTPStream {
+	if !c.ok() {
+		return &SCTPStream{c: c}
+	}
+	return c.openStream(c.startReader(false), assocID, id, ppid)
+}
+
+// AcceptStream waits for a message on a stream that has no open
+// handle and returns a new handle on that stream, with the message
+// queued for reading. The handle is bound to the association the
+// message arrived on and writes with the PPID of the message.
+//
+// AcceptStream starts the reader of [SCTPConn.Messages] if needed.
+// Once it has been called, messages on streams without a handle open
+// new handles instead of being delivered by Messages, and the reader
+// waits for the next call to AcceptStream before reading further.
+// After [SCTPConn.Close], AcceptStream returns an error wrapping
+// [ErrClosed].
+func (c *SCTPConn) AcceptStream() (*SCTPStream, error) {
+	if !c.ok() {
+		return nil, syscall.EINVAL
+	}
+	r := c.startReader(true)
+	s, ok := <-r.accept
+	if !ok {
+		return nil, r.stopErr(c)
+	}
+	return s, nil
+}
+
+// openStream returns the handle on stream id of the association
+// identified by assocID, registering a new one if needed.
+func (c *SCTPConn) openStream(r *sctpReader, assocID int32, id uint16, ppid uint32) *SCTPStream {
+	c.mu.Lock()
+	key := sctpStreamKey{assocID, id}
+	if s := c.streams[key]; s != nil {
+		c.mu.Unlock()
+		return s
+	}
+	s := &SCTPStream{
+		c:       c,
+		assocID: assocID,
+		id:      id,
+		ppid:    ppid,
+		changed: make(chan struct{}),
+	}
+	if r.stopped {
+		s.err = r.stopErr(c)
+	}
+	if c.streams == nil {
+		c.streams = make(map[sctpStreamKey]*SCTPStream)
+	}
+	c.streams[key] = s
+	c.mu.Unlock()
+
+	// Accepting the peer's reset requests must be enabled before
+	// they arrive. Failure only means that the stream cannot be reset.
+	enableStreamResetSCTP(c.fd, assocID)
+	return s
+}
+
+func (s *SCTPStream) ok() bool { return s != nil && s.c.ok() }
+
+// Read reads the data of the messages received on the stream. A read
+// may return part of a message; the rest is returned by the following
+// reads.
+func (s *SCTPStream) Read(b []byte) (int, error) {
+	if !s.ok() {
+		return 0, syscall.EINVAL
+	}
+	p, err := s.take(len(b))
+	return copy(b, p), err
+}
+
+// WriteTo implements the [io.WriterTo] interface. It writes the data
+// of each message received on the stream to w in a single call, until
+// the stream ends.
+func (s *SCTPStream) WriteTo(w io.Writer) (int64, error) {
+	if !s.ok() {
+		return 0, syscall.EINVAL
+	}
+	var written int64
+	for {
+		p, err := s.take(-1)
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+		n, err := w.Write(p)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// take waits for received data and removes up to max bytes of the
+// next message from the queue, or all of it if max is negative.
+func (s *SCTPStream) take(max int) ([]byte, error) {
+	for {
+		s.mu.Lock()
+		switch {
+		case s.closed:
+			s.mu.Unlock()
+			return nil, s.opError("read", ErrClosed)
+		case len(s.queue) > 0:
+			p := s.queue[0]
+			if max >= 0 && max < len(p) {
+				p = p[:max]
+			}
+			if s.queue[0] = s.queue[0][len(p):]; len(s.queue[0]) == 0 {
+				s.queue[0] = nil
+				s.queue = s.queue[1:]
+				if len(s.queue) == sctpStreamQueueLen-1 {
+					// Release the reader waiting in deliver.
+					s.notify()
+				}
+			}
+			s.mu.Unlock()
+			return p, nil
+		case s.eof:
+			s.mu.Unlock()
+			return nil, io.EOF
+		case s.err != nil:
+			err := s.err
+			s.mu.Unlock()
+			return nil, err
+		}
+		changed, deadline := s.changed, s.rdeadline
+		s.mu.Unlock()
+
+		if deadline.IsZero() {
+			<-changed
+			continue
+		}
+		d := time.Until(deadline)
+		if d <= 0 {
+			return nil, s.opError("read", os.ErrDeadlineExceeded)
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-changed:
+			t.Stop()
+		case <-t.C:
+			return nil, s.opError("read", os.ErrDeadlineExceeded)
+		}
+	}
+}
+
+// Write sends b as one message on the stream.
+//
+// If the send buffer is full, Write waits for space until the write
+// deadline of the handle passes, and then fails without sending b. The
+// write deadline of the [SCTPConn] applies as well.
+func (s *SCTPStream) Write(b []byte) (int, error) {
+	if !s.ok() {
+		return 0, syscall.EINVAL
+	}
+	c := s.c
+	s.mu.Lock()
+	closed, deadline := s.closed, s.wdeadline
+	info := SCTPSndInfo{Stream: s.id, PPID: s.ppid, AssocID: s.assocID}
+	var addr *SCTPAddr
+	if !connectedSCTP(c.fd) && c.fd.raddr == nil {
+		if info.AssocID == 0 {
+			info.AssocID = s.lastAssoc
+		}
+		if info.AssocID == s.lastAssoc {
+			addr = s.lastAddr
+		}
+	}
+	s.mu.Unlock()
+	if closed {
+		return 0, s.opError("write", ErrClosed)
+	}
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, s.opError("write", os.ErrDeadlineExceeded)
+	}
+	if addr == nil && info.AssocID != 0 {
+		addr = c.assocPeerAddr(info.AssocID)
+	}
+	return c.sendSCTP(b, addr, &info, deadline)
+}
+
+// ReadFrom implements the [io.ReaderFrom] interface. It reads r until
+// EOF and writes the data on the stream as messages no larger than
+// [SCTPConn.MaxMessageSize], as [SCTPConn.SendFrom] does. The write
+// deadline applies to each message as it does to Write.
+func (s *SCTPStream) ReadFrom(r io.Reader) (int64, error) {
+	if !s.ok() {
+		return 0, syscall.EINVAL
+	}
+	s.mu.Lock()
+	assocID := s.assocID
+	if assocID == 0 {
+		assocID = s.lastAssoc
+	}
+	s.mu.Unlock()
+	return sendChunks(r, s.c.sendChunkSize(assocID), s.Write)
+}
+
+// Close closes the handle. Messages received on the stream afterwards
+// are no longer buffered. If the association supports RFC 6525 stream
+// reconfiguration (see [SCTPFeatures]), Close also resets the outgoing
+// stream, which the peer sees as the end of the stream. While data
+// written on the stream is still queued for sending, the reset is
+// retried in the background until the data has drained, for at most
+// 30 seconds; Close does not wait for it.
+func (s *SCTPStream) Close() error {
+	if !s.ok() {
+		return syscall.EINVAL
+	}
+	c := s.c
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return s.opError("close", ErrClosed)
+	}
+	s.closed = true
+	s.queue = nil
+	stopped := s.err != nil
+	assocID := s.assocID
+	if assocID == 0 {
+		assocID = s.lastAssoc
+	}
+	s.notify()
+	s.mu.Unlock()
+
+	c.mu.Lock()
+	if key := (sctpStreamKey{s.assocID, s.id}); c.streams[key] == s {
+		delete(c.streams, key)
+	}
+	c.mu.Unlock()
+
+	if stopped {
+		return nil
+	}
+	if assocID == 0 && !c.peeled {
+		ids, err := assocIDsSCTP(c.fd)
+		if err != nil || len(ids) != 1 {
+			// Nothing was exchanged on an identifiable association.
+			return nil
+		}
+		assocID = ids[0]
+	}
+	err := resetStreamsSCTP(c.fd, assocID, []uint16{s.id})
+	if sctpResetBusy(err) {
+		go c.retryResetStream(assocID, s.id)
+		return nil
+	}
+	if err != nil && !sctpResetUnsupported(err) {
+		return s.opError("close", err)
+	}
+	return nil
+}
+
+// sctpResetRetryTimeout bounds the background retries of a stream
+// reset that the kernel refuses while data is queued.
+const sctpResetRetryTimeout = 30 * time.Second
+
+// retryResetStream resets outgoing stream id of the association
+// identified by assocID once the kernel accepts it, polling as
+// [SCTPConn.Drain] does. It gives up after sctpResetRetryTimeout and
+// on any other outcome, including the end of the association or the
+// closing of c.
+func (c *SCTPConn) retryResetStream(assocID int32, id uint16) {
+	deadline := time.Now().Add(sctpResetRetryTimeout)
+	delay := time.Millisecond
+	for {
+		time.Sleep(delay)
+		if time.Now().After(deadline) {
+			return
+		}
+		if err := resetStreamsSCTP(c.fd, assocID, []uint16{id}); !sctpResetBusy(err) {
+			return
+		}
+		delay = min(2*delay, 50*time.Millisecond)
+	}
+}
+
+// SetDeadline sets the read and write deadlines of the handle. They
+// are independent of the deadlines of the [SCTPConn].
+func (s *SCTPStream) SetDeadline(t time.Time) error {
+	if !s.ok() {
+		return syscall.EINVAL
+	}
+	s.mu.Lock()
+	s.rdeadline, s.wdeadline = t, t
+	s.notify()
+	s.mu.Unlock()
+	return nil
+}
+
+// SetReadDeadline sets the read deadline of the handle.
+func (s *SCTPStream) SetReadDeadline(t time.Time) error {
+	if !s.ok() {
+		return syscall.EINVAL
+	}
+	s.mu.Lock()
+	s.rdeadline = t
+	s.notify()
+	s.mu.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the handle.
+func (s *SCTPStream) SetWriteDeadline(t time.Time) error {
+	if !s.ok() {
+		return syscall.EINVAL
+	}
+	s.mu.Lock()
+	s.wdeadline = t
+	s.mu.Unlock()
+	return nil
+}
+
+// LocalAddr returns the local network address of the connection the
+// stream belongs to.
+func (s *SCTPStream) LocalAddr() Addr {
+	if !s.ok() {
+		return nil
+	}
+	return s.c.LocalAddr()
+}
+
+// RemoteAddr returns the network address of the peer of the stream:
+// the source address of the last message received if any, and
+// otherwise the address messages are sent to.
+func (s *SCTPStream) RemoteAddr() Addr {
+	if !s.ok() {
+		return nil
+	}
+	s.mu.Lock()
+	addr, assocID := s.lastAddr, s.lastAssoc
+	s.mu.Unlock()
+	if addr != nil {
+		return addr
+	}
+	if assocID == 0 {
+		assocID = s.assocID
+	}
+	if assocID != 0 {
+		if addr := s.c.assocPeerAddr(assocID); addr != nil {
+			return addr
+		}
+	}
+	return s.c.RemoteAddr()
+}
+
+func (s *SCTPStream) opError(op string, err error) error {
+	return &OpError{Op: op, Net: s.c.fd.net, Source: s.c.fd.laddr, Addr: s.c.fd.raddr, Err: err}
+}
+
+// notify wakes the goroutines waiting in Read. s.mu must be held.
+func (s *SCTPStream) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// serves reports whether the stream of s ends with the association
+// identified by assocID. A handle with assocID 0 on a listening socket
+// serves many associations and never ends.
+func (s *SCTPStream) serves(assocID int32) bool {
+	if s.assocID == assocID || s.c.peeled {
+		return true
+	}
+	return s.assocID == 0 && s.c.fd.raddr != nil
+}
+
+// sctpStreamQueueLen is the number of received messages buffered by
+// an SCTPStream before the reader waits for the handle to be read.
+const sctpStreamQueueLen = 64
+
+// deliver queues a message received on the stream, waiting while the
+// queue is full. It reports false if stop was closed meanwhile.
+func (s *SCTPStream) deliver(data []byte, addr *SCTPAddr, assocID int32, stop <-chan struct{}) bool {
+	s.mu.Lock()
+	for !s.closed && len(s.queue) >= sctpStreamQueueLen {
+		changed := s.changed
+		s.mu.Unlock()
+		select {
+		case <-changed:
+		case <-stop:
+			return false
+		}
+		s.mu.Lock()
+	}
+	defer s.mu.Unlock()
+	if s.closed {
+		return true
+	}
+	s.queue = append(s.queue, data)
+	s.lastAssoc, s.lastAddr = assocID, addr
+	s.notify()
+	return true
+}
+
+// end marks the stream as ended by the peer if it is served on
+// assocID.
+func (s *SCTPStream) end(assocID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.serves(assocID) && !s.eof {
+		s.eof = true
+		s.notify()
+	}
+}
+
+// stop records that the reader of the connection stopped with err.
+func (s *SCTPStream) stop(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err == nil {
+		s.err = err
+		s.notify()
+	}
+}
+
+// streamFor returns the handle receiving stream id of the association
+// identified by assocID, or nil.
+func (c *SCTPConn) streamFor(assocID int32, id uint16) *SCTPStream {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s := c.streams[sctpStreamKey{assocID, id}]; s != nil {
+		return s
+	}
+	return c.streams[sctpStreamKey{0, id}]
+}
+
+// observeStreams applies a notification to the stream handles of c.
+func (c *SCTPConn) observeStreams(n SCTPNotification) {
+	var (
+		assocID int32
+		ids     []uint16
+	)
+	switch n := n.(type) {
+	case *SCTPStreamResetEvent:
+		if n.Flags&SCTPStreamResetIncoming == 0 || n.Flags&(SCTPStreamResetDenied|SCTPStreamResetFailed) != 0 {
+			return
+		}
+		assocID, ids = n.AssocID, n.Streams
+	case *SCTPAssocChangeEvent:
+		if n.State == SCTPCommUp || n.State == SCTPRestart {
+			return
+		}
+		assocID = n.AssocID
+	default:
+		return
+	}
+	c.mu.Lock()
+	var streams []*SCTPStream
+	for k, s := range c.streams {
+		if len(ids) == 0 || slices.Contains(ids, k.stream) {
+			streams = append(streams, s)
+		}
+	}
+	c.mu.Unlock()
+	for _, s := range streams {
+		s.end(assocID)
+	}
+}
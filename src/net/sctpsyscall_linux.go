@@ -0,0 +1,31 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && !386
+
+package net
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// sctpU64Align is the alignment of a __u64 member of a kernel
+// structure.
+const sctpU64Align = 8
+
+func setsockoptSCTP(s, level, name int, val unsafe.Pointer, vallen uintptr) (int, syscall.Errno) {
+	r0, _, errno := syscall.Syscall6(syscall.SYS_SETSOCKOPT, uintptr(s), uintptr(level), uintptr(name), uintptr(val), vallen, 0)
+	return int(r0), errno
+}
+
+func getsockoptSCTP(s, level, name int, val unsafe.Pointer, vallen *uint32) syscall.Errno {
+	_, _, errno := syscall.Syscall6(syscall.SYS_GETSOCKOPT, uintptr(s), uintptr(level), uintptr(name), uintptr(val), uintptr(unsafe.Pointer(vallen)), 0)
+	return errno
+}
+
+func sendmsgSCTP(s int, msg *syscall.Msghdr, flags int) syscall.Errno {
+	_, _, errno := syscall.Syscall(syscall.SYS_SENDMSG, uintptr(s), uintptr(unsafe.Pointer(msg)), uintptr(flags))
+	return errno
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package net
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// sctpU64Align is the alignment of a __u64 member of a kernel
+// structure. The i386 ABI aligns it to 4 bytes.
+const sctpU64Align = 4
+
+// On 386 the socket system calls are multiplexed through socketcall;
+// see linux/net.h.
+const (
+	sysGetsockopt = 15
+	sysSetsockopt = 14
+	sysSendmsg    = 16
+)
+
+// This helper is implemented in the syscall package.
+//
+//go:linkname socketcall syscall.socketcall
+func socketcall(call int, a0, a1, a2, a3, a4, a5 uintptr) (n int, err syscall.Errno)
+
+func setsockoptSCTP(s, level, name int, val unsafe.Pointer, vallen uintptr) (int, syscall.Errno) {
+	return socketcall(sysSetsockopt, uintptr(s), uintptr(level), uintptr(name), uintptr(val), vallen, 0)
+}
+
+func getsockoptSCTP(s, level, name int, val unsafe.Pointer, vallen *uint32) syscall.Errno {
+	_, errno := socketcall(sysGetsockopt, uintptr(s), uintptr(level), uintptr(name), uintptr(val), uintptr(unsafe.Pointer(vallen)), 0)
+	return errno
+}
+
+func sendmsgSCTP(s int, msg *syscall.Msghdr, flags int) syscall.Errno {
+	_, errno := socketcall(sysSendmsg, uintptr(s), uintptr(unsafe.Pointer(msg)), uintptr(flags), 0, 0, 0)
+	return errno
+}
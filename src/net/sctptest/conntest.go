@@ -0,0 +1,345 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctptest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// MakePipe creates an SCTP association between two endpoints and
+// returns them as c1 and c2, such that messages written to c1 are read
+// by c2 and vice-versa. Each endpoint must carry this association
+// only, as a peeled-off kernel socket or a [Pipe] endpoint does. The
+// stop function closes all resources, including c1, c2 and any
+// listener, and should not be nil.
+type MakePipe func() (c1, c2 MessageConn, stop func(), err error)
+
+// TestConn tests that a [MessageConn] implementation has SCTP message
+// semantics: message boundaries, stream and PPID delivery, unordered
+// delivery, deadlines, error shapes, notifications, concurrent use and
+// close behavior. It runs against kernel sockets, [Pipe] endpoints or
+// any other implementation.
+//
+// Like golang.org/x/net/nettest.TestConn, TestConn may miss some
+// issues on a single run; run it under the race detector and more than
+// once for best effect.
+func TestConn(t *testing.T, mp MakePipe) {
+	t.Run("MessageBoundaries", func(t *testing.T) { timeoutWrapper(t, mp, testMessageBoundaries) })
+	t.Run("StreamPPID", func(t *testing.T) { timeoutWrapper(t, mp, testStreamPPID) })
+	t.Run("Unordered", func(t *testing.T) { timeoutWrapper(t, mp, testUnordered) })
+	t.Run("PastDeadline", func(t *testing.T) { timeoutWrapper(t, mp, testPastDeadline) })
+	t.Run("FutureDeadline", func(t *testing.T) { timeoutWrapper(t, mp, testFutureDeadline) })
+	t.Run("OpError", func(t *testing.T) { timeoutWrapper(t, mp, testOpError) })
+	t.Run("Notifications", func(t *testing.T) { timeoutWrapper(t, mp, testNotifications) })
+	t.Run("Concurrent", func(t *testing.T) { timeoutWrapper(t, mp, testConcurrent) })
+	t.Run("Close", func(t *testing.T) { timeoutWrapper(t, mp, testClose) })
+}
+
+type connTester func(t *testing.T, c1, c2 MessageConn)
+
+func timeoutWrapper(t *testing.T, mp MakePipe, f connTester) {
+	t.Helper()
+	c1, c2, stop, err := mp()
+	if err != nil {
+		t.Fatalf("unable to make pipe: %v", err)
+	}
+	var once sync.Once
+	defer once.Do(func() { stop() })
+	timer := time.AfterFunc(time.Minute, func() {
+		once.Do(func() {
+			t.Error("test timed out; terminating pipe")
+			stop()
+		})
+	})
+	defer timer.Stop()
+	f(t, c1, c2)
+}
+
+// readUserMessage reads the next complete user message from c,
+// skipping notifications.
+func readUserMessage(t *testing.T, c MessageConn) ([]byte, *net.SCTPRcvInfo) {
+	t.Helper()
+	var msg []byte
+	buf := make([]byte, 1024)
+	for {
+		n, _, flags, _, info, err := c.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP: %v", err)
+		}
+		if flags&net.SCTPMsgNotification != 0 {
+			continue
+		}
+		msg = append(msg, buf[:n]...)
+		if flags&net.SCTPMsgEOR != 0 {
+			return msg, info
+		}
+	}
+}
+
+// testMessageBoundaries tests that each write is read back as exactly
+// one message, whatever its size.
+func testMessageBoundaries(t *testing.T, c1, c2 MessageConn) {
+	sizes := []int{1, 7, 1024, 3000, 2}
+	for i, size := range sizes {
+		msg := bytes.Repeat([]byte{byte('a' + i)}, size)
+		if _, err := c1.WriteToSCTP(msg, nil, &net.SCTPSndInfo{}); err != nil {
+			t.Fatalf("WriteToSCTP(%d bytes): %v", size, err)
+		}
+	}
+	for i, size := range sizes {
+		got, _ := readUserMessage(t, c2)
+		if want := bytes.Repeat([]byte{byte('a' + i)}, size); !bytes.Equal(got, want) {
+			t.Fatalf("message %d: got %d bytes %.8q; want %d bytes %.8q", i, len(got), got, size, want)
+		}
+	}
+}
+
+// testStreamPPID tests that the stream and PPID of a message reach the
+// peer, in both directions.
+func testStreamPPID(t *testing.T, c1, c2 MessageConn) {
+	for _, c := range []MessageConn{c1, c2} {
+		if err := c.SetRecvRcvInfo(true); err != nil {
+			t.Fatalf("SetRecvRcvInfo: %v", err)
+		}
+	}
+	for _, tt := range []struct {
+		from, to MessageConn
+		stream   uint16
+		ppid     uint32
+	}{
+		{c1, c2, 0, 0},
+		{c1, c2, 2, 46},
+		{c2, c1, 1, 0xdeadbeef},
+	} {
+		if _, err := tt.from.WriteToSCTP([]byte("ppid"), nil, &net.SCTPSndInfo{Stream: tt.stream, PPID: tt.ppid}); err != nil {
+			t.Fatalf("WriteToSCTP: %v", err)
+		}
+		_, info := readUserMessage(t, tt.to)
+		if info == nil {
+			t.Fatal("no receive information with SCTP_RECVRCVINFO enabled")
+		}
+		if info.Stream != tt.stream || info.PPID != tt.ppid {
+			t.Errorf("got stream %d, PPID %#x; want stream %d, PPID %#x", info.Stream, info.PPID, tt.stream, tt.ppid)
+		}
+	}
+}
+
+// testUnordered tests that unordered messages are delivered and
+// flagged as such.
+func testUnordered(t *testing.T, c1, c2 MessageConn) {
+	if err := c2.SetRecvRcvInfo(true); err != nil {
+		t.Fatalf("SetRecvRcvInfo: %v", err)
+	}
+	if _, err := c1.WriteToSCTP([]byte("unordered"), nil, &net.SCTPSndInfo{Stream: 1, Flags: net.SCTPUnordered}); err != nil {
+		t.Fatalf("WriteToSCTP: %v", err)
+	}
+	got, info := readUserMessage(t, c2)
+	if string(got) != "unordered" {
+		t.Fatalf("got %q; want %q", got, "unordered")
+	}
+	if info == nil || info.Flags&net.SCTPUnordered == 0 {
+		t.Errorf("receive information %+v lacks the unordered flag", info)
+	}
+}
+
+// testPastDeadline tests that a read with an expired deadline fails
+// immediately with a timeout, and that clearing the deadline allows
+// reads again.
+func testPastDeadline(t *testing.T, c1, c2 MessageConn) {
+	c2.SetReadDeadline(aLongTimeAgo)
+	_, _, _, _, _, err := c2.ReadFromSCTP(make([]byte, 16))
+	checkForTimeoutError(t, err)
+
+	c2.SetReadDeadline(time.Time{})
+	if _, err := c1.WriteToSCTP([]byte("after"), nil, &net.SCTPSndInfo{}); err != nil {
+		t.Fatalf("WriteToSCTP: %v", err)
+	}
+	if got, _ := readUserMessage(t, c2); string(got) != "after" {
+		t.Errorf("got %q; want %q", got, "after")
+	}
+}
+
+// testFutureDeadline tests that a blocked read times out once its
+// deadline passes, and no earlier.
+func testFutureDeadline(t *testing.T, c1, c2 MessageConn) {
+	const d = 100 * time.Millisecond
+	start := time.Now()
+	c2.SetReadDeadline(start.Add(d))
+	_, _, _, _, _, err := c2.ReadFromSCTP(make([]byte, 16))
+	checkForTimeoutError(t, err)
+	if elapsed := time.Since(start); elapsed < d {
+		t.Errorf("read timed out after %v; want at least %v", elapsed, d)
+	}
+}
+
+// testOpError tests the shape of the errors returned by reads and
+// writes on a closed endpoint.
+func testOpError(t *testing.T, c1, c2 MessageConn) {
+	c1.Close()
+	_, _, _, _, _, rerr := c1.ReadFromSCTP(make([]byte, 16))
+	_, werr := c1.WriteToSCTP([]byte("x"), nil, &net.SCTPSndInfo{})
+	for _, tt := range []struct {
+		op  string
+		err error
+	}{
+		{"read", rerr},
+		{"write", werr},
+	} {
+		var oe *net.OpError
+		if !errors.As(tt.err, &oe) {
+			t.Errorf("%s error %v (%T) is not a *net.OpError", tt.op, tt.err, tt.err)
+			continue
+		}
+		if oe.Op != tt.op || !strings.HasPrefix(oe.Net, "sctp") {
+			t.Errorf("%s error has Op %q, Net %q; want Op %q, Net sctp*", tt.op, oe.Op, oe.Net, tt.op)
+		}
+		if !errors.Is(tt.err, net.ErrClosed) {
+			t.Errorf("%s error %v does not wrap net.ErrClosed", tt.op, tt.err)
+		}
+	}
+}
+
+// testNotifications tests that a graceful shutdown of the association
+// is reported to a subscribed peer.
+func testNotifications(t *testing.T, c1, c2 MessageConn) {
+	if err := c2.SubscribeEvents(net.SCTPEventMask{Association: true}); err != nil {
+		t.Fatalf("SubscribeEvents: %v", err)
+	}
+	if err := c1.CloseWrite(); err != nil {
+		t.Fatalf("CloseWrite: %v", err)
+	}
+	buf := make([]byte, 1024)
+	for {
+		n, _, flags, _, _, err := c2.ReadFromSCTP(buf)
+		if err != nil {
+			t.Fatalf("ReadFromSCTP: %v; want SHUTDOWN_COMP notification", err)
+		}
+		if flags&net.SCTPMsgNotification == 0 {
+			t.Fatalf("read user message %q; want notification", buf[:n])
+		}
+		nt, err := net.ParseSCTPNotification(buf[:n])
+		if err != nil {
+			t.Fatalf("ParseSCTPNotification: %v", err)
+		}
+		if ac, ok := nt.(*net.SCTPAssocChangeEvent); ok && ac.State == net.SCTPShutdownComplete {
+			return
+		}
+	}
+}
+
+// testConcurrent tests concurrent writers on several streams and
+// concurrent readers, checking that every message arrives intact.
+func testConcurrent(t *testing.T, c1, c2 MessageConn) {
+	const (
+		writers = 4
+		readers = 2
+		count   = 50
+	)
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Go(func() {
+			for i := range count {
+				msg := fmt.Appendf(nil, "%d:%d", w, i)
+				if _, err := c1.WriteToSCTP(msg, nil, &net.SCTPSndInfo{Stream: uint16(w)}); err != nil {
+					t.Errorf("WriteToSCTP: %v", err)
+					return
+				}
+			}
+		})
+	}
+
+	// The readers block without a deadline, so a lost wakeup hangs
+	// the test; each one stops at the first end marker it reads.
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+	var rg sync.WaitGroup
+	for range readers {
+		rg.Go(func() {
+			buf := make([]byte, 64)
+			for {
+				n, _, flags, _, _, err := c2.ReadFromSCTP(buf)
+				if err != nil {
+					t.Errorf("ReadFromSCTP: %v", err)
+					return
+				}
+				if flags&net.SCTPMsgNotification != 0 {
+					continue
+				}
+				if string(buf[:n]) == "end" {
+					return
+				}
+				mu.Lock()
+				if seen[string(buf[:n])] {
+					t.Errorf("message %q read twice", buf[:n])
+				}
+				seen[string(buf[:n])] = true
+				mu.Unlock()
+			}
+		})
+	}
+	wg.Wait()
+	for range readers {
+		if _, err := c1.WriteToSCTP([]byte("end"), nil, nil); err != nil {
+			t.Fatalf("WriteToSCTP: %v", err)
+		}
+	}
+	rg.Wait()
+	for w := range writers {
+		for i := range count {
+			if msg := fmt.Sprintf("%d:%d", w, i); !seen[msg] {
+				t.Errorf("message %q lost", msg)
+			}
+		}
+	}
+}
+
+func testClose(t *testing.T, c1, c2 MessageConn) {
+	// Close must wake every pending read, not just one.
+	const readers = 3
+	errc := make(chan error, readers)
+	for range readers {
+		go func() {
+			_, _, _, _, _, err := c2.ReadFromSCTP(make([]byte, 16))
+			errc <- err
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	if err := c2.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for range readers {
+		if err := <-errc; !errors.Is(err, net.ErrClosed) {
+			t.Errorf("pending read returned %v; want net.ErrClosed", err)
+		}
+	}
+	if err := c2.Close(); err == nil {
+		t.Error("second Close succeeded; want error")
+	}
+}
+
+var aLongTimeAgo = time.Unix(1, 0)
+
+func isTimeout(err error) bool {
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+func checkForTimeoutError(t *testing.T, err error) {
+	t.Helper()
+	if !isTimeout(err) {
+		t.Errorf("got error %v (%T); want net.Error with Timeout() = true", err, err)
+	}
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("error %v does not wrap os.ErrDeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,13 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !plan9
+
+package sctptest
+
+import "syscall"
+
+// errPipe is the error of a write on an association that has ended or
+// been shut down for writing, as returned by kernel sockets.
+var errPipe error = syscall.EPIPE
@@ -0,0 +1,11 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctptest
+
+import "errors"
+
+// errPipe is the error of a write on an association that has ended or
+// been shut down for writing. Plan 9 has no EPIPE.
+var errPipe = errors.New("broken pipe")
@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctptest
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// Notification types and states, with their Linux values.
+const (
+	assocChangeEvent    = 0x8001 // SCTP_ASSOC_CHANGE
+	peerAddrChangeEvent = 0x8002 // SCTP_PEER_ADDR_CHANGE
+	shutdownEventType   = 0x8005 // SCTP_SHUTDOWN_EVENT
+	sendFailedEventType = 0x800d // SCTP_SEND_FAILED_EVENT
+
+	commUp           = 0 // SCTP_COMM_UP
+	commLost         = 1 // SCTP_COMM_LOST
+	shutdownComplete = 3 // SCTP_SHUTDOWN_COMP
+
+	userAbortCause = 12 // User-Initiated Abort (RFC 9260)
+
+	addrAvailable   = 0 // SCTP_ADDR_AVAILABLE
+	addrUnreachable = 1 // SCTP_ADDR_UNREACHABLE
+
+	afInet  = 2  // AF_INET
+	afInet6 = 10 // AF_INET6
+
+	sizeofSockaddrStorage = 128
+)
+
+// subscribed reports whether notifications of type event are selected
+// by mask.
+func subscribed(mask net.SCTPEventMask, event uint16) bool {
+	switch event {
+	case assocChangeEvent:
+		return mask.Association
+	case peerAddrChangeEvent:
+		return mask.Address
+	case shutdownEventType:
+		return mask.Shutdown
+	case sendFailedEventType:
+		return mask.SendFailure
+	}
+	return false
+}
+
+// notification returns a notification message of type event whose
+// body follows the 8-byte struct sctp_sn_header.
+func notification(event uint16, body []byte) message {
+	b := make([]byte, 8, 8+len(body))
+	binary.NativeEndian.PutUint16(b[0:], event)
+	binary.NativeEndian.PutUint32(b[4:], uint32(8+len(body)))
+	return message{
+		data:  append(b, body...),
+		flags: net.SCTPMsgNotification | net.SCTPMsgEOR,
+		event: event,
+	}
+}
+
+// assocChange returns a struct sctp_assoc_change notification.
+func assocChange(state uint16, assocID int32) message {
+	b := make([]byte, 12)
+	binary.NativeEndian.PutUint16(b[0:], state)
+	binary.NativeEndian.PutUint16(b[4:], 10) // outbound streams
+	binary.NativeEndian.PutUint16(b[6:], 10) // inbound streams
+	binary.NativeEndian.PutUint32(b[8:], uint32(assocID))
+	return notification(assocChangeEvent, b)
+}
+
+// peerAddrChange returns a struct sctp_paddr_change notification.
+func peerAddrChange(addr *net.SCTPAddr, state int32, assocID int32) message {
+	b := make([]byte, sizeofSockaddrStorage+12)
+	putSockaddr(b, addr)
+	binary.NativeEndian.PutUint32(b[sizeofSockaddrStorage:], uint32(state))
+	binary.NativeEndian.PutUint32(b[sizeofSockaddrStorage+8:], uint32(assocID))
+	return notification(peerAddrChangeEvent, b)
+}
+
+// shutdownEvent returns a struct sctp_shutdown_event notification.
+func shutdownEvent(assocID int32) message {
+	b := make([]byte, 4)
+	binary.NativeEndian.PutUint32(b, uint32(assocID))
+	return notification(shutdownEventType, b)
+}
+
+// sendFailed returns a struct sctp_send_failed_event notification
+// returning m, a message that was never sent, with the error cause.
+func sendFailed(m message, cause uint32) message {
+	b := make([]byte, 24, 24+len(m.data))
+	binary.NativeEndian.PutUint32(b[0:], cause)
+	binary.NativeEndian.PutUint16(b[4:], m.info.Stream)
+	binary.NativeEndian.PutUint32(b[8:], m.info.PPID)
+	binary.NativeEndian.PutUint32(b[12:], m.info.Context)
+	binary.NativeEndian.PutUint32(b[16:], uint32(m.info.AssocID))
+	binary.NativeEndian.PutUint32(b[20:], uint32(m.info.AssocID))
+	return notification(sendFailedEventType, append(b, m.data...))
+}
+
+// putSockaddr writes addr to b as a Linux struct sockaddr_in or
+// sockaddr_in6.
+func putSockaddr(b []byte, addr *net.SCTPAddr) {
+	binary.BigEndian.PutUint16(b[2:], uint16(addr.Port))
+	if ip4 := addr.IP.To4(); ip4 != nil {
+		binary.NativeEndian.PutUint16(b[0:], afInet)
+		copy(b[4:8], ip4)
+		return
+	}
+	binary.NativeEndian.PutUint16(b[0:], afInet6)
+	copy(b[8:24], addr.IP.To16())
+}
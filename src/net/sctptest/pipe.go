@@ -0,0 +1,592 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctptest provides an in-memory SCTP association for tests
+// that cannot rely on kernel SCTP support.
+//
+// [Pipe] returns the two endpoints of an association. Each endpoint
+// implements the message API of [net.SCTPConn]: messages keep their
+// boundaries, stream, PPID and flags, and event notifications are
+// delivered in the Linux wire format decoded by
+// [net.ParseSCTPNotification]. Tests can inject message loss,
+// reordering across streams, path failures and aborts.
+//
+// Endpoints only block on channels and timers, so they can be used
+// inside testing/synctest bubbles when created there.
+package sctptest
+
+import (
+	"io"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// MessageConn is the part of the [*net.SCTPConn] method set
+// implemented by [Conn]. Code written against MessageConn can be
+// tested with [Pipe] and run on kernel sockets.
+type MessageConn interface {
+	net.Conn
+	ReadFrom(b []byte) (int, net.Addr, error)
+	WriteTo(b []byte, addr net.Addr) (int, error)
+	ReadFromSCTP(b []byte) (n, oobn, flags int, addr *net.SCTPAddr, info *net.SCTPRcvInfo, err error)
+	WriteToSCTP(b []byte, addr *net.SCTPAddr, info *net.SCTPSndInfo) (int, error)
+	SubscribeEvents(mask net.SCTPEventMask) error
+	SetRecvRcvInfo(on bool) error
+	CloseWrite() error
+	CloseAssoc(assocID int32) error
+	AbortAssoc(assocID int32) error
+	Resend(ev *net.SCTPSendFailedEvent, assocID int32, addr *net.SCTPAddr) (int, error)
+}
+
+var nextAssocID atomic.Int32
+
+// An assoc is the state shared by the two endpoints of a pipe.
+type assoc struct {
+	mu    sync.Mutex
+	id    int32
+	ends  [2]*Conn
+	ended bool // the association was shut down or lost
+}
+
+// A message is a user message or notification queued for reading.
+type message struct {
+	data  []byte
+	flags int
+	event uint16 // notification type, for filtering by subscription
+	info  net.SCTPRcvInfo
+	from  *net.SCTPAddr
+}
+
+// Conn is one endpoint of an in-memory SCTP association. Its methods
+// are safe for concurrent use.
+type Conn struct {
+	a     *assoc
+	side  int
+	addrs []*net.SCTPAddr
+
+	// Guarded by a.mu.
+	changed       chan struct{} // closed and replaced when queue or state changes
+	queue         []message
+	held          map[uint16][]message // outgoing messages of held streams
+	holding       map[uint16]bool
+	drop          func(b []byte, info net.SCTPSndInfo) bool
+	down          map[*net.SCTPAddr]bool // unreachable peer addresses
+	events        net.SCTPEventMask
+	rcvInfo       bool
+	closed        bool
+	writeShut     bool
+	ssn           map[uint16]uint16
+	tsn           uint32
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+var _ MessageConn = (*Conn)(nil)
+
+// Pipe returns the two endpoints of a new association between
+// 127.0.0.1:1 and 127.0.0.1:2.
+func Pipe() (*Conn, *Conn) {
+	return PipeAddrs(
+		[]*net.SCTPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: 1}},
+		[]*net.SCTPAddr{{IP: net.IPv4(127, 0, 0, 1), Port: 2}},
+	)
+}
+
+// PipeAddrs is like [Pipe] but gives the endpoints the given local
+// addresses. Multiple addresses make an endpoint multihomed; the first
+// address is its primary path. PipeAddrs panics if either list is
+// empty.
+//
+// A COMM_UP notification is queued on both endpoints. As for every
+// notification, it is returned by reads only once the matching event
+// is subscribed with [Conn.SubscribeEvents].
+func PipeAddrs(addrs1, addrs2 []*net.SCTPAddr) (*Conn, *Conn) {
+	if len(addrs1) == 0 || len(addrs2) == 0 {
+		panic("sctptest: endpoint without addresses")
+	}
+	a := &assoc{id: nextAssocID.Add(1)}
+	for i, addrs := range [][]*net.SCTPAddr{addrs1, addrs2} {
+		a.ends[i] = &Conn{
+			a:       a,
+			side:    i,
+			addrs:   addrs,
+			changed: make(chan struct{}),
+			held:    make(map[uint16][]message),
+			holding: make(map[uint16]bool),
+			down:    make(map[*net.SCTPAddr]bool),
+			ssn:     make(map[uint16]uint16),
+		}
+	}
+	for _, c := range a.ends {
+		c.pushLocked(assocChange(commUp, a.id))
+	}
+	return a.ends[0], a.ends[1]
+}
+
+// AssocID returns the identifier of the association, the same on both
+// endpoints.
+func (c *Conn) AssocID() int32 { return c.a.id }
+
+func (c *Conn) peer() *Conn { return c.a.ends[1-c.side] }
+
+// pushLocked queues m for reading on c. The caller must hold c.a.mu.
+func (c *Conn) pushLocked(m message) {
+	c.queue = append(c.queue, m)
+	c.notifyLocked()
+}
+
+// notifyLocked wakes every goroutine waiting in a read on c. The
+// caller must hold c.a.mu.
+func (c *Conn) notifyLocked() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// primaryLocked returns the first address of c that its peer can
+// reach. The caller must hold c.a.mu.
+func (c *Conn) primaryLocked() *net.SCTPAddr {
+	for _, addr := range c.addrs {
+		if !c.peer().down[addr] {
+			return addr
+		}
+	}
+	return c.addrs[0]
+}
+
+func (c *Conn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "sctp", Source: c.addrs[0], Addr: c.peer().addrs[0], Err: err}
+}
+
+// ReadFromSCTP reads the next message or subscribed notification.
+// If b is too short for the message, the rest is returned by the next
+// reads and [net.SCTPMsgEOR] is only set on the last one. info
+// is non-nil for user messages once [Conn.SetRecvRcvInfo] is enabled.
+// After the association ends and the queued messages are read,
+// ReadFromSCTP returns io.EOF.
+func (c *Conn) ReadFromSCTP(b []byte) (n, oobn, flags int, addr *net.SCTPAddr, info *net.SCTPRcvInfo, err error) {
+	for {
+		a := c.a
+		a.mu.Lock()
+		if c.closed {
+			a.mu.Unlock()
+			return 0, 0, 0, nil, nil, c.opError("read", net.ErrClosed)
+		}
+		for len(c.queue) > 0 && c.queue[0].event != 0 && !subscribed(c.events, c.queue[0].event) {
+			c.queue = c.queue[1:]
+		}
+		if len(c.queue) > 0 {
+			m := &c.queue[0]
+			n = copy(b, m.data)
+			flags = m.flags &^ net.SCTPMsgEOR
+			addr = m.from
+			if m.event == 0 && c.rcvInfo {
+				ri := m.info
+				info = &ri
+			}
+			if n == len(m.data) {
+				flags |= m.flags & net.SCTPMsgEOR
+				c.queue = c.queue[1:]
+			} else {
+				m.data = m.data[n:]
+			}
+			a.mu.Unlock()
+			return n, 0, flags, addr, info, nil
+		}
+		ended := a.ended
+		changed, deadline := c.changed, c.readDeadline
+		a.mu.Unlock()
+
+		if ended {
+			return 0, 0, 0, nil, nil, io.EOF
+		}
+		if err := wait(changed, deadline); err != nil {
+			return 0, 0, 0, nil, nil, c.opError("read", err)
+		}
+	}
+}
+
+// wait blocks until changed is closed or deadline passes.
+func wait(changed <-chan struct{}, deadline time.Time) error {
+	if deadline.IsZero() {
+		<-changed
+		return nil
+	}
+	d := time.Until(deadline)
+	if d <= 0 {
+		return os.ErrDeadlineExceeded
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-changed:
+		return nil
+	case <-t.C:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+// WriteToSCTP sends b as one message to the peer. addr is ignored,
+// since the association has a single peer. The SCTPUnordered,
+// SCTPEOF and SCTPAbort flags of info are honored.
+func (c *Conn) WriteToSCTP(b []byte, addr *net.SCTPAddr, info *net.SCTPSndInfo) (int, error) {
+	var si net.SCTPSndInfo
+	if info != nil {
+		si = *info
+	}
+	if si.AssocID != 0 && si.AssocID != c.a.id {
+		return 0, c.opError("write", syscall.EINVAL)
+	}
+
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c.closed {
+		return 0, c.opError("write", net.ErrClosed)
+	}
+	if !c.writeDeadline.IsZero() && !time.Now().Before(c.writeDeadline) {
+		return 0, c.opError("write", os.ErrDeadlineExceeded)
+	}
+	if a.ended {
+		return 0, c.opError("write", errPipe)
+	}
+	if si.Flags&net.SCTPAbort != 0 {
+		c.abortLocked()
+		return len(b), nil
+	}
+	if si.Flags&net.SCTPEOF != 0 {
+		c.shutdownLocked()
+		return len(b), nil
+	}
+	if c.writeShut {
+		return 0, c.opError("write", errPipe)
+	}
+	if c.drop != nil && c.drop(b, si) {
+		return len(b), nil
+	}
+
+	c.tsn++
+	m := message{
+		data:  append([]byte(nil), b...),
+		flags: net.SCTPMsgEOR,
+		from:  c.primaryLocked(),
+		info: net.SCTPRcvInfo{
+			Stream:  si.Stream,
+			PPID:    si.PPID,
+			TSN:     c.tsn,
+			CumTSN:  c.tsn,
+			Context: si.Context,
+			AssocID: a.id,
+		},
+	}
+	if si.Flags&net.SCTPUnordered != 0 {
+		m.info.Flags = net.SCTPUnordered
+		c.peer().pushLocked(m)
+		return len(b), nil
+	}
+	m.info.SSN = c.ssn[si.Stream]
+	c.ssn[si.Stream]++
+	if c.holding[si.Stream] {
+		c.held[si.Stream] = append(c.held[si.Stream], m)
+		return len(b), nil
+	}
+	c.peer().pushLocked(m)
+	return len(b), nil
+}
+
+// Read reads the data of the next user message, skipping
+// notifications.
+func (c *Conn) Read(b []byte) (int, error) {
+	for {
+		n, _, flags, _, _, err := c.ReadFromSCTP(b)
+		if err != nil || flags&net.SCTPMsgNotification == 0 {
+			return n, err
+		}
+	}
+}
+
+// Write sends b as one message on stream 0.
+func (c *Conn) Write(b []byte) (int, error) {
+	return c.WriteToSCTP(b, nil, nil)
+}
+
+// ReadFrom reads the next user message and returns its source address.
+func (c *Conn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		n, _, flags, addr, _, err := c.ReadFromSCTP(b)
+		if err != nil {
+			return n, nil, err
+		}
+		if flags&net.SCTPMsgNotification == 0 {
+			return n, addr, nil
+		}
+	}
+}
+
+// WriteTo sends b as one message on stream 0. addr is ignored.
+func (c *Conn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	return c.WriteToSCTP(b, nil, nil)
+}
+
+// Close closes the endpoint. If the association is still up, it is
+// shut down gracefully, as by Close on a kernel socket.
+func (c *Conn) Close() error {
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c.closed {
+		return c.opError("close", net.ErrClosed)
+	}
+	if !a.ended {
+		c.shutdownLocked()
+	}
+	c.closed = true
+	c.queue = nil
+	c.notifyLocked()
+	return nil
+}
+
+// CloseWrite shuts down the association gracefully: messages already
+// sent, including those of held streams, are delivered, then both
+// endpoints receive SHUTDOWN_COMP. Messages not yet read stay
+// readable.
+func (c *Conn) CloseWrite() error {
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if c.closed {
+		return c.opError("close", net.ErrClosed)
+	}
+	if !a.ended {
+		c.shutdownLocked()
+	}
+	return nil
+}
+
+// CloseAssoc is CloseWrite for the association identified by assocID.
+func (c *Conn) CloseAssoc(assocID int32) error {
+	if assocID != c.a.id {
+		return c.opError("close", syscall.EINVAL)
+	}
+	return c.CloseWrite()
+}
+
+// AbortAssoc is [Conn.Abort] for the association identified by assocID.
+func (c *Conn) AbortAssoc(assocID int32) error {
+	if assocID != c.a.id {
+		return c.opError("close", syscall.EINVAL)
+	}
+	c.Abort()
+	return nil
+}
+
+// shutdownLocked delivers the held messages of both endpoints and
+// ends the association gracefully. The caller must hold c.a.mu.
+func (c *Conn) shutdownLocked() {
+	a := c.a
+	for _, e := range a.ends {
+		e.releaseAllLocked()
+	}
+	c.writeShut = true
+	c.peer().pushLocked(shutdownEvent(a.id))
+	for _, e := range a.ends {
+		e.pushLocked(assocChange(shutdownComplete, a.id))
+	}
+	a.ended = true
+}
+
+// Abort aborts the association from c: messages of held streams are
+// discarded and both endpoints receive COMM_LOST. An endpoint
+// subscribed to SendFailure then receives an SCTP_SEND_FAILED_EVENT
+// for each of its discarded messages. Messages already delivered stay
+// readable.
+func (c *Conn) Abort() {
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.ended {
+		c.abortLocked()
+	}
+}
+
+// abortLocked ends the association abortively. The caller must hold
+// c.a.mu.
+func (c *Conn) abortLocked() {
+	a := c.a
+	for _, e := range a.ends {
+		e.pushLocked(assocChange(commLost, a.id))
+		for _, stream := range slices.Sorted(maps.Keys(e.held)) {
+			for _, m := range e.held[stream] {
+				e.pushLocked(sendFailed(m, userAbortCause))
+			}
+		}
+		clear(e.held)
+	}
+	a.ended = true
+}
+
+// Drop installs a loss filter on the messages written by c: each
+// message for which drop reports true is silently discarded. A nil
+// drop removes the filter.
+func (c *Conn) Drop(drop func(b []byte, info net.SCTPSndInfo) bool) {
+	c.a.mu.Lock()
+	c.drop = drop
+	c.a.mu.Unlock()
+}
+
+// Resend writes the message returned by ev again with its stream, PPID
+// and context, as [net.SCTPConn.Resend] does. assocID must be 0 or the
+// association of c, and addr is ignored.
+func (c *Conn) Resend(ev *net.SCTPSendFailedEvent, assocID int32, addr *net.SCTPAddr) (int, error) {
+	if ev == nil {
+		return 0, c.opError("write", syscall.EINVAL)
+	}
+	info := ev.Info
+	info.AssocID = assocID
+	return c.WriteToSCTP(ev.Payload, nil, &info)
+}
+
+// HoldStream holds back the ordered messages written by c on stream,
+// so that messages written later on other streams overtake them.
+func (c *Conn) HoldStream(stream uint16) {
+	c.a.mu.Lock()
+	c.holding[stream] = true
+	c.a.mu.Unlock()
+}
+
+// ReleaseStream delivers, in order, the messages held on stream and
+// stops holding it.
+func (c *Conn) ReleaseStream(stream uint16) {
+	c.a.mu.Lock()
+	defer c.a.mu.Unlock()
+	c.releaseLocked(stream)
+}
+
+func (c *Conn) releaseLocked(stream uint16) {
+	for _, m := range c.held[stream] {
+		c.peer().pushLocked(m)
+	}
+	delete(c.held, stream)
+	delete(c.holding, stream)
+}
+
+func (c *Conn) releaseAllLocked() {
+	for stream := range c.holding {
+		c.releaseLocked(stream)
+	}
+}
+
+// FailPath marks addr, an address of the peer, as unreachable from c.
+// c receives a PEER_ADDR_CHANGE notification with state
+// SCTP_ADDR_UNREACHABLE, and messages from the peer come from its next
+// reachable address. When the last peer address fails, the association
+// is lost and both endpoints receive COMM_LOST.
+func (c *Conn) FailPath(addr *net.SCTPAddr) error {
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	p := c.peer()
+	pa := p.lookupLocked(addr)
+	if pa == nil || a.ended {
+		return c.opError("set", syscall.EINVAL)
+	}
+	if c.down[pa] {
+		return nil
+	}
+	c.down[pa] = true
+	c.pushLocked(peerAddrChange(pa, addrUnreachable, a.id))
+	for _, addr := range p.addrs {
+		if !c.down[addr] {
+			return nil
+		}
+	}
+	c.abortLocked()
+	return nil
+}
+
+// RestorePath marks addr, an address of the peer, as reachable from c
+// again. c receives a PEER_ADDR_CHANGE notification with state
+// SCTP_ADDR_AVAILABLE.
+func (c *Conn) RestorePath(addr *net.SCTPAddr) error {
+	a := c.a
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	pa := c.peer().lookupLocked(addr)
+	if pa == nil || a.ended {
+		return c.opError("set", syscall.EINVAL)
+	}
+	if !c.down[pa] {
+		return nil
+	}
+	delete(c.down, pa)
+	c.pushLocked(peerAddrChange(pa, addrAvailable, a.id))
+	return nil
+}
+
+// lookupLocked returns the address of c equal to addr, or nil.
+func (c *Conn) lookupLocked(addr *net.SCTPAddr) *net.SCTPAddr {
+	if addr == nil {
+		return nil
+	}
+	for _, a := range c.addrs {
+		if a.IP.Equal(addr.IP) && a.Port == addr.Port {
+			return a
+		}
+	}
+	return nil
+}
+
+// SubscribeEvents selects the notifications returned by reads.
+// Association, Address, Shutdown and SendFailure events are generated.
+func (c *Conn) SubscribeEvents(mask net.SCTPEventMask) error {
+	c.a.mu.Lock()
+	c.events = mask
+	c.notifyLocked()
+	c.a.mu.Unlock()
+	return nil
+}
+
+// SetRecvRcvInfo controls whether ReadFromSCTP returns the receive
+// information of user messages.
+func (c *Conn) SetRecvRcvInfo(on bool) error {
+	c.a.mu.Lock()
+	c.rcvInfo = on
+	c.a.mu.Unlock()
+	return nil
+}
+
+// LocalAddr returns the primary address of c.
+func (c *Conn) LocalAddr() net.Addr { return c.addrs[0] }
+
+// RemoteAddr returns the primary address of the peer.
+func (c *Conn) RemoteAddr() net.Addr { return c.peer().addrs[0] }
+
+// SetDeadline sets the read and write deadlines.
+func (c *Conn) SetDeadline(t time.Time) error {
+	c.SetReadDeadline(t)
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	c.a.mu.Lock()
+	c.readDeadline = t
+	c.notifyLocked()
+	c.a.mu.Unlock()
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline. Writes never block, so
+// only a deadline already in the past has an effect.
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	c.a.mu.Lock()
+	c.writeDeadline = t
+	c.a.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctptest
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+var _ MessageConn = (*net.SCTPConn)(nil)
+
+func readMsg(t *testing.T, c *Conn) ([]byte, int, *net.SCTPRcvInfo) {
+	t.Helper()
+	buf := make([]byte, 256)
+	n, _, flags, _, info, err := c.ReadFromSCTP(buf)
+	if err != nil {
+		t.Fatalf("ReadFromSCTP: %v", err)
+	}
+	return buf[:n], flags, info
+}
+
+func readEvent(t *testing.T, c *Conn) net.SCTPNotification {
+	t.Helper()
+	b, flags, _ := readMsg(t, c)
+	if flags&net.SCTPMsgNotification == 0 {
+		t.Fatalf("read message %q; want notification", b)
+	}
+	n, err := net.ParseSCTPNotification(b)
+	if err != nil {
+		t.Fatalf("ParseSCTPNotification: %v", err)
+	}
+	return n
+}
+
+func TestPipeMessages(t *testing.T) {
+	c1, c2 := Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	c2.SetRecvRcvInfo(true)
+
+	if _, err := c1.WriteToSCTP([]byte("hello"), nil, &net.SCTPSndInfo{Stream: 3, PPID: 46}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c1.WriteToSCTP([]byte("world"), nil, &net.SCTPSndInfo{Stream: 3, PPID: 46}); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []string{"hello", "world"} {
+		b, flags, info := readMsg(t, c2)
+		if string(b) != want || flags&net.SCTPMsgEOR == 0 {
+			t.Fatalf("message %d = %q, flags %#x; want %q with MSG_EOR", i, b, flags, want)
+		}
+		if info == nil || info.Stream != 3 || info.PPID != 46 || info.SSN != uint16(i) || info.AssocID != c1.AssocID() {
+			t.Fatalf("message %d info = %+v", i, info)
+		}
+	}
+
+	// A short buffer splits the message; MSG_EOR marks the last part.
+	c1.Write([]byte("abcdef"))
+	buf := make([]byte, 4)
+	n, _, flags, _, _, err := c2.ReadFromSCTP(buf)
+	if err != nil || string(buf[:n]) != "abcd" || flags&net.SCTPMsgEOR != 0 {
+		t.Fatalf("first part = %q, %#x, %v", buf[:n], flags, err)
+	}
+	n, _, flags, _, _, err = c2.ReadFromSCTP(buf)
+	if err != nil || string(buf[:n]) != "ef" || flags&net.SCTPMsgEOR == 0 {
+		t.Fatalf("second part = %q, %#x, %v", buf[:n], flags, err)
+	}
+}
+
+func TestPipeNotifications(t *testing.T) {
+	c1, c2 := Pipe()
+	defer c1.Close()
+	c2.SubscribeEvents(net.SCTPEventMask{Association: true, Shutdown: true})
+
+	if ev, ok := readEvent(t, c2).(*net.SCTPAssocChangeEvent); !ok || ev.State != net.SCTPCommUp || ev.AssocID != c1.AssocID() {
+		t.Fatalf("first event = %+v; want COMM_UP", ev)
+	}
+	c1.Write([]byte("last"))
+	c1.CloseWrite()
+	if b, _, _ := readMsg(t, c2); string(b) != "last" {
+		t.Fatalf("read %q; want %q", b, "last")
+	}
+	if ev, ok := readEvent(t, c2).(*net.SCTPUnknownEvent); !ok || ev.Type != shutdownEventType {
+		t.Fatalf("got %+v; want SCTP_SHUTDOWN_EVENT", ev)
+	}
+	if ev, ok := readEvent(t, c2).(*net.SCTPAssocChangeEvent); !ok || ev.State != net.SCTPShutdownComplete {
+		t.Fatalf("got %+v; want SHUTDOWN_COMP", ev)
+	}
+	if _, _, _, _, _, err := c2.ReadFromSCTP(make([]byte, 16)); err != io.EOF {
+		t.Fatalf("read after shutdown: %v; want EOF", err)
+	}
+	if _, err := c2.Write([]byte("x")); !errors.Is(err, errPipe) {
+		t.Fatalf("write after shutdown: %v; want EPIPE", err)
+	}
+}
+
+func TestPipeReorderAndLoss(t *testing.T) {
+	c1, c2 := Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	c2.SetRecvRcvInfo(true)
+
+	c1.HoldStream(1)
+	c1.Drop(func(b []byte, info net.SCTPSndInfo) bool { return string(b) == "lost" })
+	c1.WriteToSCTP([]byte("a"), nil, &net.SCTPSndInfo{Stream: 1})
+	c1.WriteToSCTP([]byte("lost"), nil, &net.SCTPSndInfo{Stream: 2})
+	c1.WriteToSCTP([]byte("b"), nil, &net.SCTPSndInfo{Stream: 2})
+	c1.ReleaseStream(1)
+
+	for _, want := range []string{"b", "a"} {
+		if b, _, _ := readMsg(t, c2); string(b) != want {
+			t.Fatalf("read %q; want %q", b, want)
+		}
+	}
+}
+
+func TestPipePathFailure(t *testing.T) {
+	a1 := []*net.SCTPAddr{{IP: net.IPv4(10, 0, 0, 1), Port: 3868}}
+	a2 := []*net.SCTPAddr{
+		{IP: net.IPv4(10, 0, 1, 2), Port: 3868},
+		{IP: net.ParseIP("fd00::2"), Port: 3868},
+	}
+	c1, c2 := PipeAddrs(a1, a2)
+	defer c1.Close()
+	defer c2.Close()
+	c1.SubscribeEvents(net.SCTPEventMask{Address: true, Association: true})
+	readEvent(t, c1) // COMM_UP
+
+	if err := c1.FailPath(a2[0]); err != nil {
+		t.Fatal(err)
+	}
+	if ev, ok := readEvent(t, c1).(*net.SCTPPeerAddrChangeEvent); !ok || ev.State != net.SCTPAddrUnreachable || ev.Addr.String() != a2[0].String() {
+		t.Fatalf("got %+v; want SCTP_PEER_ADDR_CHANGE unreachable for %v", ev, a2[0])
+	}
+	c2.Write([]byte("failover"))
+	n, addr, err := c1.ReadFrom(make([]byte, 16))
+	if err != nil || n != len("failover") || addr.String() != a2[1].String() {
+		t.Fatalf("ReadFrom = %d, %v, %v; want message from %v", n, addr, err, a2[1])
+	}
+
+	c1.FailPath(a2[1])
+	readEvent(t, c1) // SCTP_PEER_ADDR_CHANGE
+	if ev, ok := readEvent(t, c1).(*net.SCTPAssocChangeEvent); !ok || ev.State != net.SCTPCommLost {
+		t.Fatalf("got %+v; want COMM_LOST", ev)
+	}
+}
+
+func TestPipeAbort(t *testing.T) {
+	c1, c2 := Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	c2.SubscribeEvents(net.SCTPEventMask{Association: true})
+	readEvent(t, c2) // COMM_UP
+
+	c1.HoldStream(0)
+	c1.Write([]byte("discarded"))
+	if err := c1.AbortAssoc(c1.AssocID()); err != nil {
+		t.Fatal(err)
+	}
+	if ev, ok := readEvent(t, c2).(*net.SCTPAssocChangeEvent); !ok || ev.State != net.SCTPCommLost {
+		t.Fatalf("got %+v; want COMM_LOST", ev)
+	}
+	if _, _, _, _, _, err := c2.ReadFromSCTP(make([]byte, 16)); err != io.EOF {
+		t.Fatalf("read after abort: %v; want EOF", err)
+	}
+}
+
+func TestPipeSendFailed(t *testing.T) {
+	c1, c2 := Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	c1.SubscribeEvents(net.SCTPEventMask{Association: true, SendFailure: true})
+	readEvent(t, c1) // COMM_UP
+
+	c1.HoldStream(2)
+	if _, err := c1.WriteToSCTP([]byte("relayed"), nil, &net.SCTPSndInfo{Stream: 2, PPID: 46, Context: 77}); err != nil {
+		t.Fatal(err)
+	}
+	c1.Abort()
+	if ev, ok := readEvent(t, c1).(*net.SCTPAssocChangeEvent); !ok || ev.State != net.SCTPCommLost {
+		t.Fatalf("got %+v; want COMM_LOST", ev)
+	}
+	ev, ok := readEvent(t, c1).(*net.SCTPSendFailedEvent)
+	if !ok {
+		t.Fatalf("got %T; want *net.SCTPSendFailedEvent", ev)
+	}
+	if string(ev.Payload) != "relayed" || !ev.Unsent || ev.Info.Stream != 2 || ev.Info.PPID != 46 ||
+		ev.Info.Context != 77 || ev.Info.AssocID != c1.AssocID() {
+		t.Fatalf("got %+v; want the unsent message with its send info", ev)
+	}
+
+	// The relay moves the message to another association.
+	c3, c4 := Pipe()
+	defer c3.Close()
+	defer c4.Close()
+	c4.SetRecvRcvInfo(true)
+	if _, err := c3.Resend(ev, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, _, info := readMsg(t, c4)
+	if string(b) != "relayed" || info.Stream != 2 || info.PPID != 46 {
+		t.Fatalf("resent message = %q, %+v; want %q on stream 2 with PPID 46", b, info, "relayed")
+	}
+}
+
+func TestPipeSynctest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		c1, c2 := Pipe()
+		defer c1.Close()
+		defer c2.Close()
+
+		start := time.Now()
+		c2.SetReadDeadline(start.Add(time.Minute))
+		if _, err := c2.Read(make([]byte, 16)); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("Read: %v; want deadline exceeded", err)
+		}
+		if d := time.Since(start); d != time.Minute {
+			t.Fatalf("Read returned after %v; want %v", d, time.Minute)
+		}
+
+		c2.SetReadDeadline(time.Time{})
+		go func() {
+			time.Sleep(time.Second)
+			c1.Write([]byte("late"))
+		}()
+		buf := make([]byte, 16)
+		n, err := c2.Read(buf)
+		if err != nil || string(buf[:n]) != "late" {
+			t.Fatalf("Read = %q, %v", buf[:n], err)
+		}
+	})
+}
+
+func TestPipeConformance(t *testing.T) {
+	TestConn(t, func() (c1, c2 MessageConn, stop func(), err error) {
+		p1, p2 := Pipe()
+		return p1, p2, func() { p1.Close(); p2.Close() }, nil
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sctpvar publishes the counters of SCTP connections, as
+// returned by [net.SCTPConn.Stats], through package expvar.
+//
+// Nothing is published unless requested: [Publish] exports one
+// connection, and a [Conns] variable exports a changing set of them,
+// such as the associations accepted by a server:
+//
+//	conns := sctpvar.NewConns("sctp")
+//	for {
+//		c, err := ln.AcceptSCTP()
+//		...
+//		conns.Add(c.RemoteAddr().String(), c)
+//		go func() {
+//			defer conns.Delete(c.RemoteAddr().String())
+//			serve(c)
+//		}()
+//	}
+//
+// The variables are served as JSON by the expvar handler, at
+// /debug/vars.
+package sctpvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Publish publishes the counters of c as the variable name. Like
+// [expvar.Publish], it panics if name is already registered.
+func Publish(name string, c *net.SCTPConn) {
+	expvar.Publish(name, Func(c))
+}
+
+// Func returns a variable reporting the counters of c when read.
+func Func(c *net.SCTPConn) expvar.Func {
+	return func() any { return c.Stats() }
+}
+
+// Conns is an [expvar.Var] reporting the counters of a set of
+// connections as a JSON object keyed by connection name. Its methods
+// are safe for concurrent use.
+type Conns struct {
+	mu    sync.Mutex
+	conns map[string]*net.SCTPConn
+}
+
+// NewConns returns a new set of connections published as the
+// variable name. Like [expvar.Publish], it panics if name is already
+// registered.
+func NewConns(name string) *Conns {
+	v := new(Conns)
+	expvar.Publish(name, v)
+	return v
+}
+
+// Add adds c to the set under key, replacing any connection already
+// added under it.
+func (v *Conns) Add(key string, c *net.SCTPConn) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.conns == nil {
+		v.conns = make(map[string]*net.SCTPConn)
+	}
+	v.conns[key] = c
+}
+
+// Delete removes the connection added under key.
+func (v *Conns) Delete(key string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.conns, key)
+}
+
+// String implements the [expvar.Var] interface. The connections are
+// ordered by key.
+func (v *Conns) String() string {
+	v.mu.Lock()
+	keys := make([]string, 0, len(v.conns))
+	stats := make(map[string]net.SCTPStats, len(v.conns))
+	for k, c := range v.conns {
+		keys = append(keys, k)
+		stats[k] = c.Stats()
+	}
+	v.mu.Unlock()
+	slices.Sort(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		kj, _ := json.Marshal(k)
+		sj, err := json.Marshal(stats[k])
+		if err != nil {
+			sj = []byte("null")
+		}
+		b.Write(kj)
+		b.WriteString(": ")
+		b.Write(sj)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sctpvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"net"
+	"testing"
+)
+
+func TestConns(t *testing.T) {
+	v := NewConns("sctpvar-test")
+	if expvar.Get("sctpvar-test") != v {
+		t.Fatal("NewConns did not publish the variable")
+	}
+	if got := v.String(); got != "{}" {
+		t.Errorf("empty String() = %s; want {}", got)
+	}
+	v.Add("b", new(net.SCTPConn))
+	v.Add("a", new(net.SCTPConn))
+	var got map[string]net.SCTPStats
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("String() is not valid JSON: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d connections; want 2", len(got))
+	}
+	v.Delete("a")
+	got = nil
+	if err := json.Unmarshal([]byte(v.String()), &got); err != nil {
+		t.Fatalf("String() is not valid JSON: %v", err)
+	}
+	if _, ok := got["b"]; !ok || len(got) != 1 {
+		t.Errorf("after Delete got %v; want only b", got)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	ln, err := net.ListenSCTP("sctp4", &net.SCTPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("SCTP unavailable: %v", err)
+	}
+	defer ln.Close()
+	c, err := net.DialSCTP("sctp4", nil, ln.LocalAddr().(*net.SCTPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	Publish("sctpvar-conn", c)
+	if _, err := c.WriteToSCTP([]byte("hello"), nil, &net.SCTPSndInfo{Stream: 2, PPID: 7}); err != nil {
+		t.Fatal(err)
+	}
+	var s net.SCTPStats
+	if err := json.Unmarshal([]byte(expvar.Get("sctpvar-conn").String()), &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Streams) != 1 || s.Streams[0].Stream != 2 || s.Streams[0].PPID != 7 || s.Streams[0].BytesOut != 5 {
+		t.Errorf("published Streams = %+v; want 5 bytes out on stream 2, PPID 7", s.Streams)
+	}
+}